@@ -0,0 +1,195 @@
+package xerror
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
+
+// RetryClass describes whether, and how, a call that failed with a status code can be retried.
+type RetryClass uint8
+
+const (
+	// RetryClassNone is for calls that shouldn't be retried, since retrying won't help.
+	RetryClassNone RetryClass = iota
+	// RetryClassDirect is for calls that can be retried directly, using an exponential backoff strategy. See
+	// Error.IsDirectlyRetryable.
+	RetryClassDirect
+	// RetryClassHigherLevel is for calls that can't be retried directly, but should be retried at a higher level in the
+	// system. See Error.IsRetryableAtHigherLevel.
+	RetryClassHigherLevel
+)
+
+// CodeInfo describes a status code.
+type CodeInfo struct {
+	// Code is the status code.
+	Code codes.Code
+	// Name is the canonical name of the status code, as declared by google.rpc.Code, ex. "CANCELLED" or "NOT_FOUND".
+	// It's the name used in the "status" field of the AIP-193 error response, see https://google.aip.dev/193.
+	Name string
+	// HTTPStatus is the HTTP status code that the status code is mapped to, as declared by google.rpc.Code.
+	HTTPStatus int
+	// DefaultMessage is the message of errors with the status code, when there is no more specific message.
+	DefaultMessage string
+	// RetryClass describes whether, and how, a call that failed with the status code can be retried.
+	RetryClass RetryClass
+	// LogLevel is the log level suggested for errors with the status code. It's the one used by the constructors and
+	// From, except for NewRequestDataLoss and NewResourceExhausted, whose log level depends on the cause of the error.
+	LogLevel LogLevel
+}
+
+// codeInfos is the registry of status codes, indexed by status code.
+var codeInfos = [...]CodeInfo{
+	codes.OK: {
+		Code:       codes.OK,
+		Name:       "OK",
+		HTTPStatus: http.StatusOK,
+	},
+	codes.Canceled: {
+		Code:           codes.Canceled,
+		Name:           "CANCELLED",
+		HTTPStatus:     499, // Client Closed Request
+		DefaultMessage: "request cancelled by the client",
+		LogLevel:       LogLevelInfo,
+	},
+	codes.Unknown: {
+		Code:           codes.Unknown,
+		Name:           "UNKNOWN",
+		HTTPStatus:     http.StatusInternalServerError,
+		DefaultMessage: "something unknown happened",
+		LogLevel:       LogLevelError,
+	},
+	codes.InvalidArgument: {
+		Code:           codes.InvalidArgument,
+		Name:           "INVALID_ARGUMENT",
+		HTTPStatus:     http.StatusBadRequest,
+		DefaultMessage: msgInvalidArgs,
+		LogLevel:       LogLevelInfo,
+	},
+	codes.DeadlineExceeded: {
+		Code:           codes.DeadlineExceeded,
+		Name:           "DEADLINE_EXCEEDED",
+		HTTPStatus:     http.StatusGatewayTimeout,
+		DefaultMessage: "the operation timed out (it might have succeeded though)",
+		LogLevel:       LogLevelWarn,
+	},
+	codes.NotFound: {
+		Code:           codes.NotFound,
+		Name:           "NOT_FOUND",
+		HTTPStatus:     http.StatusNotFound,
+		DefaultMessage: "requested resource not found",
+		LogLevel:       LogLevelInfo,
+	},
+	codes.AlreadyExists: {
+		Code:           codes.AlreadyExists,
+		Name:           "ALREADY_EXISTS",
+		HTTPStatus:     http.StatusConflict,
+		DefaultMessage: "resource already exists",
+		LogLevel:       LogLevelInfo,
+	},
+	codes.PermissionDenied: {
+		Code:           codes.PermissionDenied,
+		Name:           "PERMISSION_DENIED",
+		HTTPStatus:     http.StatusForbidden,
+		DefaultMessage: "permission denied",
+		LogLevel:       LogLevelInfo,
+	},
+	codes.ResourceExhausted: {
+		Code:           codes.ResourceExhausted,
+		Name:           "RESOURCE_EXHAUSTED",
+		HTTPStatus:     http.StatusTooManyRequests,
+		DefaultMessage: "the request cannot be completed because the quota has been exhausted",
+		RetryClass:     RetryClassHigherLevel,
+		LogLevel:       LogLevelInfo,
+	},
+	codes.FailedPrecondition: {
+		Code:           codes.FailedPrecondition,
+		Name:           "FAILED_PRECONDITION",
+		HTTPStatus:     http.StatusBadRequest,
+		DefaultMessage: msgPreconditionFailures,
+		LogLevel:       LogLevelWarn,
+	},
+	codes.Aborted: {
+		Code:           codes.Aborted,
+		Name:           "ABORTED",
+		HTTPStatus:     http.StatusConflict,
+		DefaultMessage: "the operation was aborted",
+		RetryClass:     RetryClassHigherLevel,
+		LogLevel:       LogLevelWarn,
+	},
+	codes.OutOfRange: {
+		Code:           codes.OutOfRange,
+		Name:           "OUT_OF_RANGE",
+		HTTPStatus:     http.StatusBadRequest,
+		DefaultMessage: msgOutOfRangeErrors,
+		LogLevel:       LogLevelInfo,
+	},
+	codes.Unimplemented: {
+		Code:           codes.Unimplemented,
+		Name:           "UNIMPLEMENTED",
+		HTTPStatus:     http.StatusNotImplemented,
+		DefaultMessage: "not implemented",
+		LogLevel:       LogLevelInfo,
+	},
+	codes.Internal: {
+		Code:           codes.Internal,
+		Name:           "INTERNAL",
+		HTTPStatus:     http.StatusInternalServerError,
+		DefaultMessage: "an internal server error happened",
+		LogLevel:       LogLevelError,
+	},
+	codes.Unavailable: {
+		Code:           codes.Unavailable,
+		Name:           "UNAVAILABLE",
+		HTTPStatus:     http.StatusServiceUnavailable,
+		DefaultMessage: "the operation is currently unavailable",
+		RetryClass:     RetryClassDirect,
+		LogLevel:       LogLevelInfo,
+	},
+	codes.DataLoss: {
+		Code:           codes.DataLoss,
+		Name:           "DATA_LOSS",
+		HTTPStatus:     http.StatusInternalServerError,
+		DefaultMessage: "server data loss",
+		LogLevel:       LogLevelError,
+	},
+	codes.Unauthenticated: {
+		Code:           codes.Unauthenticated,
+		Name:           "UNAUTHENTICATED",
+		HTTPStatus:     http.StatusUnauthorized,
+		DefaultMessage: "the request does not have valid authentication credentials",
+		LogLevel:       LogLevelInfo,
+	},
+}
+
+// CodeInfoOf returns the description of the status code. Status codes that aren't declared by google.rpc.Code are
+// described as Unknown, but keep their code.
+func CodeInfoOf(code codes.Code) CodeInfo {
+	if int(code) >= len(codeInfos) {
+		info := codeInfos[codes.Unknown]
+		info.Code = code
+		return info
+	}
+	return codeInfos[code]
+}
+
+// CodeInfos returns the descriptions of all status codes declared by google.rpc.Code, ordered by status code.
+func CodeInfos() []CodeInfo {
+	infos := make([]CodeInfo, len(codeInfos))
+	copy(infos, codeInfos[:])
+	return infos
+}
+
+// CodeFromName returns the status code with the canonical name, see CodeInfo.Name. The American spelling "CANCELED"
+// is accepted as well. It returns false if there is no status code with the name.
+func CodeFromName(name string) (codes.Code, bool) {
+	if name == "CANCELED" {
+		return codes.Canceled, true
+	}
+	for _, info := range codeInfos {
+		if info.Name == name {
+			return info.Code, true
+		}
+	}
+	return codes.Unknown, false
+}
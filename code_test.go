@@ -0,0 +1,114 @@
+package xerror
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeInfos(t *testing.T) {
+	// Every status code known by grpc-go, whose unknown codes are formatted as "Code(17)"
+	var all []codes.Code
+	for c := codes.OK; !strings.HasPrefix(c.String(), "Code("); c++ {
+		all = append(all, c)
+	}
+	require.Len(t, CodeInfos(), len(all), "the registry must describe every status code")
+
+	for _, code := range all {
+		t.Run(code.String(), func(t *testing.T) {
+			require := require.New(t)
+			info := CodeInfoOf(code)
+			require.Equal(code, info.Code)
+			require.Equal(runtime.HTTPStatusFromCode(code), info.HTTPStatus)
+
+			// The name is the one grpc-go accepts in JSON
+			var fromJSON codes.Code
+			require.NoError(fromJSON.UnmarshalJSON([]byte(strconv.Quote(info.Name))))
+			require.Equal(code, fromJSON)
+			fromName, ok := CodeFromName(info.Name)
+			require.True(ok)
+			require.Equal(code, fromName)
+
+			if code == codes.OK {
+				return
+			}
+			require.NotEmpty(info.DefaultMessage)
+			require.NotEqual(LogLevelUnspecified, info.LogLevel)
+		})
+	}
+}
+
+func TestCodeInfoOf_UndeclaredCode(t *testing.T) {
+	info := CodeInfoOf(codes.Code(42))
+	require.Equal(t, codes.Code(42), info.Code)
+	require.Equal(t, "UNKNOWN", info.Name)
+	require.Equal(t, 500, info.HTTPStatus)
+}
+
+func TestCodeFromName(t *testing.T) {
+	code, ok := CodeFromName("CANCELED")
+	require.True(t, ok)
+	require.Equal(t, codes.Canceled, code)
+
+	_, ok = CodeFromName("NotFound")
+	require.False(t, ok)
+}
+
+func TestCodeInfos_MatchConstructors(t *testing.T) {
+	tests := []struct {
+		xerr *Error
+	}{
+		{xerr: NewNotFound(ResourceInfo{})},
+		{xerr: NewAlreadyExists(ResourceInfo{})},
+		{xerr: NewUnknown(nil)},
+		{xerr: NewInternal(nil)},
+		{xerr: NewNotImplemented()},
+		{xerr: NewUnavailable(nil)},
+		{xerr: NewDeadlineExceeded()},
+		{xerr: NewServerDataLoss(nil)},
+		{xerr: NewQuotaFailure("project:1", "quota exceeded")},
+		{xerr: NewInvalidArgumentBatch(nil)},
+		{xerr: NewPreconditionFailureBatch(nil)},
+		{xerr: NewOutOfRangeBatch(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.xerr.StatusCode().String(), func(t *testing.T) {
+			info := CodeInfoOf(tt.xerr.StatusCode())
+			require.Equal(t, info.DefaultMessage, tt.xerr.StatusMessage())
+			require.Equal(t, info.LogLevel, tt.xerr.LogLevel())
+		})
+	}
+}
+
+func TestCodeInfos_LogLevelsMatchConstructors(t *testing.T) {
+	opts := ErrorInfoOptions{Error: errors.New("boom"), Reason: "BOOM"}
+	tests := []struct {
+		name string
+		xerr *Error
+		want LogLevel
+	}{
+		{name: "invalid argument", xerr: NewInvalidArgument("age", "must be set"), want: CodeInfoOf(codes.InvalidArgument).LogLevel},
+		{name: "precondition failure", xerr: NewPreconditionFailure("TOS", "TOS", "not accepted"), want: CodeInfoOf(codes.FailedPrecondition).LogLevel},
+		{name: "out of range", xerr: NewOutOfRange("age", "must be less than 150"), want: CodeInfoOf(codes.OutOfRange).LogLevel},
+		{name: "unauthenticated", xerr: NewUnauthenticated(opts), want: CodeInfoOf(codes.Unauthenticated).LogLevel},
+		{name: "permission denied", xerr: NewPermissionDenied(opts), want: CodeInfoOf(codes.PermissionDenied).LogLevel},
+		{name: "not found batch", xerr: NewNotFoundBatch(nil), want: CodeInfoOf(codes.NotFound).LogLevel},
+		{name: "aborted", xerr: NewAborted(opts), want: CodeInfoOf(codes.Aborted).LogLevel},
+		{name: "already exists batch", xerr: NewAlreadyExistsBatch(nil), want: CodeInfoOf(codes.AlreadyExists).LogLevel},
+		{name: "quota failure batch", xerr: NewQuotaFailureBatch(nil), want: CodeInfoOf(codes.ResourceExhausted).LogLevel},
+		{name: "cancelled", xerr: NewCancelled(), want: CodeInfoOf(codes.Canceled).LogLevel},
+		// The documented exceptions
+		{name: "resource exhausted", xerr: NewResourceExhausted(opts), want: LogLevelWarn},
+		{name: "request data loss", xerr: NewRequestDataLoss(opts), want: LogLevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.xerr.LogLevel())
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package xerror
 
+import "google.golang.org/grpc/codes"
+
 /* -------------------------------------------------------------------------- */
 /*                          Server-initialized errors                         */
 /* -------------------------------------------------------------------------- */
@@ -57,7 +59,7 @@ package xerror
 //
 // For when to use this error type, see the ErrorGuide function for more information.
 func NewInvalidArgument(field, description string) *Error {
-	return maker.newInvalidArgument(field, description)
+	return observeConstructed(maker.newInvalidArgument(field, description))
 }
 
 // NewInvalidArgumentBatch creates a new InvalidArgument error. This is the batch version that adds multiple field
@@ -65,7 +67,7 @@ func NewInvalidArgument(field, description string) *Error {
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewInvalidArgumentBatch(violations []BadRequestViolation) *Error {
-	return maker.newInvalidArgumentErrors(violations)
+	return observeConstructed(maker.newInvalidArgumentErrors(violations))
 }
 
 // NewFailedPrecondition creates a new FailedPrecondition error.
@@ -88,13 +90,13 @@ func NewInvalidArgumentBatch(violations []BadRequestViolation) *Error {
 //
 // For when to use this error type, see the ErrorGuide function for more information.
 func NewPreconditionFailure(subject, typ, description string) *Error {
-	return maker.newPreconditionFailure(subject, typ, description)
+	return observeConstructed(maker.newPreconditionFailure(subject, typ, description))
 }
 
 // NewFailedPreconditionBatch creates a new FailedPrecondition error. This is the batch version that adds multiple
 // precondition violations.
 func NewPreconditionFailureBatch(violations []PreconditionViolation) *Error {
-	return maker.newPreconditionFailures(violations)
+	return observeConstructed(maker.newPreconditionFailures(violations))
 }
 
 // NewOutOfRange creates a new OutOfRange error.
@@ -103,35 +105,35 @@ func NewPreconditionFailureBatch(violations []PreconditionViolation) *Error {
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewOutOfRange(field, description string) *Error {
-	return maker.newOutOfRangeError(field, description)
+	return observeConstructed(maker.newOutOfRangeError(field, description))
 }
 
 // NewOutOfRangeBatch creates a new OutOfRange error. This is the batch version that adds multiple field violations.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewOutOfRangeBatch(violations []BadRequestViolation) *Error {
-	return maker.newOutOfRangeErrors(violations)
+	return observeConstructed(maker.newOutOfRangeErrors(violations))
 }
 
 // NewUnauthenticated creates a new Unauthenticated error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewUnauthenticated(opts ErrorInfoOptions) *Error {
-	return maker.newUnauthenticatedError(opts)
+	return observeConstructed(maker.newUnauthenticatedError(opts))
 }
 
 // NewPermissionDenied creates a new PermissionDenied error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewPermissionDenied(opts ErrorInfoOptions) *Error {
-	return maker.newPermissionDeniedError(opts)
+	return observeConstructed(maker.newPermissionDeniedError(opts))
 }
 
 // NewNotFound creates a new NotFound error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewNotFound(info ResourceInfo) *Error {
-	return maker.newNotFound(info)
+	return observeConstructed(maker.newNotFound(info))
 }
 
 // NewNotFoundBatch creates a new NotFound error. This is the batch version that adds information about multiple
@@ -139,21 +141,21 @@ func NewNotFound(info ResourceInfo) *Error {
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewNotFoundBatch(infos []ResourceInfo) *Error {
-	return maker.newBatchNotFound(infos)
+	return observeConstructed(maker.newBatchNotFound(infos))
 }
 
 // NewAborted creates a new Aborted error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewAborted(opts ErrorInfoOptions) *Error {
-	return maker.newAborted(opts)
+	return observeConstructed(maker.newAborted(opts))
 }
 
 // NewAlreadyExists creates a new AlreadyExists error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewAlreadyExists(info ResourceInfo) *Error {
-	return maker.newAlreadyExists(info)
+	return observeConstructed(maker.newAlreadyExists(info))
 }
 
 // NewAlreadyExistsBatch creates a new AlreadyExists error. This is the batch version that adds information about
@@ -161,7 +163,7 @@ func NewAlreadyExists(info ResourceInfo) *Error {
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewAlreadyExistsBatch(infos []ResourceInfo) *Error {
-	return maker.newAlreadyExistsBatch(infos)
+	return observeConstructed(maker.newAlreadyExistsBatch(infos))
 }
 
 // NewQuotaFailure creates a new QuotaFailure error, which is a specialized version of a resource exhausted error.
@@ -181,75 +183,93 @@ func NewAlreadyExistsBatch(infos []ResourceInfo) *Error {
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewQuotaFailure(subject, description string) *Error {
-	return maker.newQuotaFailure(subject, description)
+	return observeConstructed(maker.newQuotaFailure(subject, description))
 }
 
 // NewQuotaFailureBatch creates a new QuotaFailure error. This is the batch version that adds multiple quota violations.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewQuotaFailureBatch(violations []QuotaViolation) *Error {
-	return maker.newQuotaFailureBatch(violations)
+	return observeConstructed(maker.newQuotaFailureBatch(violations))
 }
 
 // NewResourceExhausted creates a new ResourceExhausted error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewResourceExhausted(opts ErrorInfoOptions) *Error {
-	return maker.newResourceExhausted(opts)
+	return observeConstructed(maker.newResourceExhausted(opts))
 }
 
 // NewCancelled creates a new Cancelled error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewCancelled() *Error {
-	return maker.newCancelledError()
+	return observeConstructed(maker.newCancelledError())
 }
 
 // NewServerDataLoss creates a new DataLoss error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewServerDataLoss(err error) *Error {
-	return maker.newServerDataLoss(err)
+	return observeConstructed(maker.newServerDataLoss(err))
 }
 
 // NewRequestDataLoss creates a new DataLoss error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewRequestDataLoss(opts ErrorInfoOptions) *Error {
-	return maker.newRequestDataLoss(opts)
+	return observeConstructed(maker.newRequestDataLoss(opts))
 }
 
 // NewUnknown creates a new Unknown error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewUnknown(err error) *Error {
-	return maker.newUnknown(err)
+	return observeConstructed(maker.newUnknown(err))
 }
 
 // NewInternal creates a new Internal error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewInternal(err error) *Error {
-	return maker.newInternalError(err)
+	return observeConstructed(maker.newInternalError(err))
 }
 
 // NewNotImplemented creates a new NotImplemented error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewNotImplemented() *Error {
-	return maker.newNotImplemented()
+	return observeConstructed(maker.newNotImplemented())
 }
 
 // NewUnavailable creates a new Unavailable error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewUnavailable(err error) *Error {
-	return maker.newUnavailable(err)
+	return observeConstructed(maker.newUnavailable(err))
 }
 
 // NewDeadlineExceeded creates a new DeadlineExceeded error.
 //
 // For when to use this, see the ErrorGuide function for more information.
 func NewDeadlineExceeded() *Error {
-	return maker.newDeadlineExceeded()
+	return observeConstructed(maker.newDeadlineExceeded())
+}
+
+// NewSentinel creates an error that is meant to be compared with other errors using errors.Is. It matches any error
+// with the same status code, domain and reason, see Error.Is. Unlike the other constructors, it takes the domain as a
+// parameter, so it can be used to declare package-level variables before Init is called, and for domains of other
+// services. It panics with ErrInvalidSentinel if the domain or the reason is empty. The log level is the one of the
+// status code, see CodeInfo.
+//
+// Since a sentinel is shared, it is never modified. The methods that modify an error return a modified copy of the
+// sentinel instead, and From returns a copy of it, so that a sentinel returned by a handler isn't modified by the
+// interceptors and responders either. This means that the result of such a method must be used, ex.
+// return ErrOutOfStock.AddVar("sku", sku).
+//
+// Ex.
+//
+//	var ErrOutOfStock = xerror.NewSentinel(codes.ResourceExhausted, "order.greatpencils.com", "OUT_OF_STOCK")
+func NewSentinel(code codes.Code, domain, reason string) *Error {
+	return maker.newSentinel(code, domain, reason)
 }
@@ -0,0 +1,116 @@
+package xerror
+
+import (
+	"context"
+	"sync"
+)
+
+type varsContextKey struct{}
+
+type varScopeContextKey struct{}
+
+// varScope is a mutable set of variables shared by all contexts derived from the context it was installed in, see
+// WithVarScope.
+type varScope struct {
+	mu   sync.Mutex
+	vars []Var
+}
+
+// WithVarScope returns a copy of ctx that carries an empty variable scope. Variables added with WithVars to ctx, or to
+// any context derived from it, are added to the scope instead of to the derived context. This makes them visible to
+// everyone holding a context derived from ctx, in particular to the code that installed the scope, no matter how deep
+// down the call stack they were added.
+//
+// The xgrpc, xconnect and xtwirp interceptors install a scope before calling the handler, and so does xhttp.VarScope
+// for HTTP handlers, which means that variables added anywhere in a handler are merged into the errors it returns. If
+// ctx already carries a scope, then ctx is returned as is.
+func WithVarScope(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(varScopeContextKey{}).(*varScope); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, varScopeContextKey{}, &varScope{})
+}
+
+// WithVars adds the given variables to ctx. It is used to make values that are known in one place, such as a tenant id
+// or the name of the operation, part of the runtime state of any error that is returned by the request, without having
+// to add them at every return site.
+//
+// If ctx carries a variable scope, see WithVarScope, then the variables are added to the scope and ctx is returned as
+// is. Otherwise, a copy of ctx that carries the given variables, in addition to the variables already carried by ctx,
+// is returned.
+//
+// If a variable with the same name is already carried by ctx, its value is replaced, but it keeps its position.
+// Variables with an empty name or a nil value are ignored, just like with the AddVar method.
+//
+// The variables are merged into an error's runtime state by the WithContext method, which is called by the xgrpc
+// interceptor and the xhttp responders.
+//
+// Ex.
+//
+//	ctx = xerror.WithVars(ctx, xerror.Var{Name: "tenant_id", Value: tenantID})
+func WithVars(ctx context.Context, vars ...Var) context.Context {
+	if scope, ok := ctx.Value(varScopeContextKey{}).(*varScope); ok {
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		scope.vars = mergeVarList(scope.vars, vars)
+		return ctx
+	}
+	existing, _ := ctx.Value(varsContextKey{}).([]Var)
+	merged := make([]Var, len(existing), len(existing)+len(vars))
+	copy(merged, existing)
+	return context.WithValue(ctx, varsContextKey{}, mergeVarList(merged, vars))
+}
+
+// VarsFrom returns the variables carried by ctx, in the order they were added. Variables carried by ctx itself come
+// before the ones in its variable scope. The returned slice must not be modified.
+func VarsFrom(ctx context.Context) []Var {
+	if ctx == nil {
+		return nil
+	}
+	vars, _ := ctx.Value(varsContextKey{}).([]Var)
+	scope, ok := ctx.Value(varScopeContextKey{}).(*varScope)
+	if !ok {
+		return vars
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	merged := make([]Var, len(vars), len(vars)+len(scope.vars))
+	copy(merged, vars)
+	return mergeVarList(merged, scope.vars)
+}
+
+// mergeVars appends the variables carried by ctx to the runtime state. Variables already in the runtime state take
+// precedence, since they were added closer to where the error was encountered, so context variables with the same name
+// are skipped.
+func (xerr *Error) mergeVars(ctx context.Context) {
+	for _, v := range VarsFrom(ctx) {
+		if indexOfVar(xerr.runtimeState, v.Name) >= 0 {
+			continue
+		}
+		xerr.runtimeState = append(xerr.runtimeState, v)
+	}
+}
+
+// mergeVarList merges the variables into dst, replacing the values of the variables that are already in dst.
+func mergeVarList(dst, vars []Var) []Var {
+	for _, v := range vars {
+		if v.Name == "" || v.Value == nil {
+			continue
+		}
+		if i := indexOfVar(dst, v.Name); i >= 0 {
+			dst[i] = v
+			continue
+		}
+		dst = append(dst, v)
+	}
+	return dst
+}
+
+func indexOfVar(vars []Var, name string) int {
+	for i, v := range vars {
+		if v.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,70 @@
+package xerror
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithVars(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	parent := WithVars(context.Background(),
+		Var{Name: "tenant_id", Value: "tenant-1"},
+		Var{Name: "", Value: "ignored"},
+		Var{Name: "ignored", Value: nil},
+	)
+
+	/* ---------------------------------- When ---------------------------------- */
+	child := WithVars(parent,
+		Var{Name: "operation", Value: "CreateUser"},
+		Var{Name: "tenant_id", Value: "tenant-2"}, // replaces the value but keeps the position
+	)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal([]Var{{Name: "tenant_id", Value: "tenant-1"}}, VarsFrom(parent))
+	require.Equal([]Var{{Name: "tenant_id", Value: "tenant-2"}, {Name: "operation", Value: "CreateUser"}}, VarsFrom(child))
+}
+
+func TestWithVarScope(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	outer := WithVars(context.Background(), Var{Name: "service", Value: "orders"})
+	scoped := WithVarScope(outer)
+	require.Equal(t, scoped, WithVarScope(scoped), "a scope must not be installed twice")
+
+	// The handler adds variables to a context derived from the scoped one, and discards it
+	handler := func(ctx context.Context) error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		_ = WithVars(ctx, Var{Name: "tenant_id", Value: "tenant-1"}, Var{Name: "service", Value: "billing"})
+		return NewInternal(errors.New("boom")).AddVar("tenant_id", "tenant-2")
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	xerr := From(handler(scoped)).WithContext(scoped)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal([]Var{{Name: "tenant_id", Value: "tenant-2"}, {Name: "service", Value: "billing"}}, xerr.RuntimeState())
+	// The context the scope was installed in is left untouched
+	require.Equal([]Var{{Name: "service", Value: "orders"}}, VarsFrom(outer))
+}
+
+func TestWithVarScope_Concurrent(t *testing.T) {
+	ctx := WithVarScope(context.Background())
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = WithVars(ctx, Var{Name: name, Value: 1})
+			_ = VarsFrom(ctx)
+		}()
+	}
+	wg.Wait()
+	require.Len(t, VarsFrom(ctx), len(names))
+}
@@ -0,0 +1,216 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tobbstr/xerror/xerrorpb"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SchemaVersion is the version of the schema used by MarshalJSON, MarshalBinary and Envelope. It's incremented
+// whenever the schema changes in a way that isn't backwards compatible.
+const SchemaVersion = 1
+
+// ErrUnsupportedSchemaVersion is returned when unmarshalling an error that was marshalled with a schema version that
+// isn't supported.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+
+// MarshalText implements encoding.TextMarshaler. The log level is marshalled as its name, see String.
+func (lvl LogLevel) MarshalText() ([]byte, error) {
+	return []byte(lvl.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the names returned by String.
+func (lvl *LogLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*lvl = LogLevelDebug
+	case "info":
+		*lvl = LogLevelInfo
+	case "warn":
+		*lvl = LogLevelWarn
+	case "error":
+		*lvl = LogLevelError
+	case "unspecified", "":
+		*lvl = LogLevelUnspecified
+	default:
+		return fmt.Errorf("unknown log level %q", text)
+	}
+	return nil
+}
+
+// jsonError is the JSON schema of an Error.
+type jsonError struct {
+	Version       int             `json:"version"`
+	LogLevel      LogLevel        `json:"logLevel"`
+	Status        json.RawMessage `json:"status"`
+	DetailsHidden bool            `json:"detailsHidden"`
+	RuntimeState  []jsonVar       `json:"runtimeState"`
+	TraceContext  *TraceContext   `json:"traceContext,omitempty"`
+}
+
+type jsonVar struct {
+	Name      string `json:"name"`
+	Value     any    `json:"value"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+}
+
+// MarshalJSON marshals the error to JSON, so it can be persisted or sent through a message queue, and later restored
+// with UnmarshalJSON. The schema is versioned, see SchemaVersion. The status is marshalled in the protobuf JSON format,
+// including its details.
+//
+// Sensitive runtime state values are masked, see Var.SafeValue, which means that they can't be restored. The other
+// values are restored as the types that encoding/json decodes JSON values into, ex. numbers become float64 values.
+// Values that can't be marshalled to JSON, ex. channels or NaN, are marshalled as strings formatted with fmt.Sprint,
+// both here and in Envelope, so that an error is encoded the same way by every transport.
+//
+// Ex.
+//
+//	{
+//	  "version": 1,
+//	  "logLevel": "warn",
+//	  "status": {"code": 5, "message": "requested resource not found", "details": [...]},
+//	  "detailsHidden": false,
+//	  "runtimeState": [{"name": "user_id", "value": 1}, {"name": "email", "value": "[REDACTED]", "sensitive": true}]
+//	}
+func (xerr *Error) MarshalJSON() ([]byte, error) {
+	st, err := protojson.Marshal(xerr.status.Proto())
+	if err != nil {
+		return nil, fmt.Errorf("marshalling status: %w", err)
+	}
+	je := jsonError{
+		Version:       SchemaVersion,
+		LogLevel:      xerr.logLevel,
+		Status:        st,
+		DetailsHidden: xerr.detailsHidden,
+		RuntimeState:  make([]jsonVar, len(xerr.runtimeState)),
+	}
+	for i, v := range xerr.runtimeState {
+		je.RuntimeState[i] = jsonVar{Name: v.Name, Value: jsonValueFrom(v), Sensitive: v.IsSensitive()}
+	}
+	if xerr.traceContext.Valid {
+		je.TraceContext = &xerr.traceContext.Value
+	}
+	return json.Marshal(je)
+}
+
+// UnmarshalJSON restores an error marshalled by MarshalJSON.
+func (xerr *Error) UnmarshalJSON(b []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(b, &je); err != nil {
+		return err
+	}
+	if je.Version != SchemaVersion {
+		return fmt.Errorf("version %d: %w", je.Version, ErrUnsupportedSchemaVersion)
+	}
+	st := &spb.Status{}
+	if err := protojson.Unmarshal(je.Status, st); err != nil {
+		return fmt.Errorf("unmarshalling status: %w", err)
+	}
+
+	*xerr = Error{
+		logLevel:      je.LogLevel,
+		status:        *status.FromProto(st),
+		detailsHidden: je.DetailsHidden,
+	}
+	for _, v := range je.RuntimeState {
+		xerr.runtimeState = append(xerr.runtimeState, Var{Name: v.Name, Value: v.Value, Sensitive: v.Sensitive})
+	}
+	if je.TraceContext != nil {
+		xerr.traceContext = newValidOptional(*je.TraceContext)
+	}
+	return nil
+}
+
+// Envelope returns the error as an ErrorEnvelope, which carries all parts of the error, including the log level and
+// the runtime state. As with MarshalJSON, sensitive runtime state values are masked, and values that can't be
+// marshalled to JSON are converted to strings.
+func (xerr *Error) Envelope() *xerrorpb.ErrorEnvelope {
+	env := &xerrorpb.ErrorEnvelope{
+		Version:       SchemaVersion,
+		LogLevel:      xerrorpb.LogLevel(xerr.logLevel),
+		Status:        xerr.status.Proto(),
+		DetailsHidden: xerr.detailsHidden,
+		RuntimeState:  make([]*xerrorpb.Var, 0, len(xerr.runtimeState)),
+	}
+	for _, v := range xerr.runtimeState {
+		value := &structpb.Value{}
+		if err := protojson.Unmarshal(jsonValueFrom(v), value); err != nil {
+			// Unreachable, since the value is valid JSON
+			value = structpb.NewStringValue(fmt.Sprint(v.SafeValue()))
+		}
+		env.RuntimeState = append(env.RuntimeState, &xerrorpb.Var{
+			Name:      v.Name,
+			Value:     value,
+			Sensitive: v.IsSensitive(),
+		})
+	}
+	if xerr.traceContext.Valid {
+		env.TraceContext = &xerrorpb.TraceContext{
+			TraceId: xerr.traceContext.Value.TraceID,
+			SpanId:  xerr.traceContext.Value.SpanID,
+		}
+	}
+	return env
+}
+
+// FromEnvelope restores an error from an ErrorEnvelope, see Error.Envelope. The runtime state values are restored as
+// the types that encoding/json decodes JSON values into, ex. numbers become float64 values.
+func FromEnvelope(env *xerrorpb.ErrorEnvelope) (*Error, error) {
+	if env.GetVersion() != SchemaVersion {
+		return nil, fmt.Errorf("version %d: %w", env.GetVersion(), ErrUnsupportedSchemaVersion)
+	}
+	xerr := &Error{
+		logLevel:      LogLevel(env.GetLogLevel()),
+		status:        *status.FromProto(env.GetStatus()),
+		detailsHidden: env.GetDetailsHidden(),
+	}
+	for _, v := range env.GetRuntimeState() {
+		xerr.runtimeState = append(xerr.runtimeState, Var{
+			Name:      v.GetName(),
+			Value:     v.GetValue().AsInterface(),
+			Sensitive: v.GetSensitive(),
+		})
+	}
+	if tc := env.GetTraceContext(); tc != nil {
+		xerr.traceContext = newValidOptional(TraceContext{TraceID: tc.GetTraceId(), SpanID: tc.GetSpanId()})
+	}
+	return xerr, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The error is marshalled as the protobuf encoding of its
+// ErrorEnvelope, see Envelope.
+func (xerr *Error) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(xerr.Envelope())
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It restores an error marshalled by MarshalBinary.
+func (xerr *Error) UnmarshalBinary(b []byte) error {
+	env := &xerrorpb.ErrorEnvelope{}
+	if err := proto.Unmarshal(b, env); err != nil {
+		return err
+	}
+	restored, err := FromEnvelope(env)
+	if err != nil {
+		return err
+	}
+	*xerr = *restored
+	return nil
+}
+
+// jsonValueFrom returns the JSON representation of the safe value of the variable, see Var.SafeValue. If the value
+// can't be marshalled to JSON, then it is marshalled as a string formatted with fmt.Sprint.
+func jsonValueFrom(v Var) json.RawMessage {
+	value := v.SafeValue()
+	if b, err := json.Marshal(value); err == nil {
+		return b
+	}
+	b, _ := json.Marshal(fmt.Sprint(value))
+	return b
+}
@@ -0,0 +1,102 @@
+package xerror
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror/xerrorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestError_RoundTrip(t *testing.T) {
+	newErr := func() *Error {
+		xerr := NewInvalidArgumentBatch([]BadRequestViolation{
+			{Field: "fullName", Description: "must be set"},
+			{Field: "emailAddresses[1].email", Description: "must be a valid email address"},
+		}).
+			SetLogLevel(LogLevelWarn).
+			SetDebugInfo("validation failed", []string{"main.go:12"}).
+			AddVar("user_id", 1).
+			AddVar("labels", map[string]any{"team": "core"}).
+			AddVar("ratio", math.NaN()). // can't be marshalled to JSON, so it's converted to a string
+			AddSecretVar("password", "hunter2")
+		xerr.traceContext = newValidOptional(TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"})
+		return xerr.HideDetails()
+	}
+	wantVars := []Var{
+		{Name: "user_id", Value: float64(1)},
+		{Name: "labels", Value: map[string]any{"team": "core"}},
+		{Name: "ratio", Value: "NaN"},
+		{Name: "password", Value: RedactedValue, Sensitive: true},
+	}
+
+	tests := []struct {
+		name      string
+		marshal   func(xerr *Error) ([]byte, error)
+		unmarshal func(b []byte, xerr *Error) error
+	}{
+		{
+			name:      "json",
+			marshal:   func(xerr *Error) ([]byte, error) { return json.Marshal(xerr) },
+			unmarshal: func(b []byte, xerr *Error) error { return json.Unmarshal(b, xerr) },
+		},
+		{
+			name:      "binary",
+			marshal:   (*Error).MarshalBinary,
+			unmarshal: func(b []byte, xerr *Error) error { return xerr.UnmarshalBinary(b) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			want := newErr()
+			require := require.New(t)
+			b, err := tt.marshal(want)
+			require.NoError(err)
+			require.NotContains(string(b), "hunter2")
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := &Error{}
+			err = tt.unmarshal(b, got)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.NoError(err)
+			require.True(proto.Equal(want.StatusProto(), got.StatusProto()), "status: %v", got.StatusProto())
+			require.Equal(codes.InvalidArgument, got.StatusCode())
+			require.Equal(want.BadRequestViolations(), got.BadRequestViolations())
+			require.Equal(LogLevelWarn, got.LogLevel())
+			require.True(got.IsDetailsHidden())
+			require.Equal(wantVars, got.RuntimeState())
+			require.Equal(want.TraceContext(), got.TraceContext())
+		})
+	}
+}
+
+func TestError_UnmarshalUnsupportedVersion(t *testing.T) {
+	var xerr Error
+	err := json.Unmarshal([]byte(`{"version": 2, "status": {"code": 5}}`), &xerr)
+	require.True(t, errors.Is(err, ErrUnsupportedSchemaVersion), "got error: %v", err)
+
+	b, err := proto.Marshal(&xerrorpb.ErrorEnvelope{Version: 2})
+	require.NoError(t, err)
+	err = xerr.UnmarshalBinary(b)
+	require.True(t, errors.Is(err, ErrUnsupportedSchemaVersion), "got error: %v", err)
+}
+
+func TestError_UnmarshalBinaryIgnoresUnknownFields(t *testing.T) {
+	b, err := NewCancelled().MarshalBinary()
+	require.NoError(t, err)
+	b = protowire.AppendTag(b, 100, protowire.BytesType)
+	b = protowire.AppendString(b, "added by a newer version")
+	b = protowire.AppendTag(b, 101, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, 42)
+
+	var xerr Error
+	require.NoError(t, xerr.UnmarshalBinary(b))
+	require.Equal(t, codes.Canceled, xerr.StatusCode())
+}
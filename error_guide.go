@@ -36,7 +36,7 @@ type requestIssue struct{}
 //
 // This case is a "CANCELLED" error.
 func (requestIssue) Cancelled() func() *Error {
-	return maker.newCancelledError
+	return NewCancelled
 }
 
 // InvalidArgument is used when a request is rejected due to invalid input.
@@ -54,7 +54,7 @@ type invalidArgIssue struct{}
 //
 // This case is an "INVALID_ARGUMENT" error.
 func (invalidArgIssue) Other() func(field, description string) *Error {
-	return maker.newInvalidArgument
+	return NewInvalidArgument
 }
 
 // OutOfRange is a specialized type of invalid argument that occurs when a value is outside the acceptable range.
@@ -66,7 +66,7 @@ func (invalidArgIssue) Other() func(field, description string) *Error {
 //
 // This case is an "OUT_OF_RANGE" error.
 func (invalidArgIssue) OutOfRange() func(field, description string) *Error {
-	return maker.newOutOfRangeError
+	return NewOutOfRange
 }
 
 // NotFound is a specialized type of invalid argument that occurs when a requested resource cannot be found.
@@ -77,7 +77,7 @@ func (invalidArgIssue) OutOfRange() func(field, description string) *Error {
 //
 // This case is a "NOT_FOUND" error.
 func (invalidArgIssue) NotFound() func(info ResourceInfo) *Error {
-	return maker.newNotFound
+	return NewNotFound
 }
 
 // DataLoss is a specialized type of invalid argument that occurs when the integrity of data is compromised.
@@ -90,7 +90,7 @@ func (invalidArgIssue) NotFound() func(info ResourceInfo) *Error {
 //
 // This case is a "DATA_LOSS" error.
 func (invalidArgIssue) DataLoss() func(opts ErrorInfoOptions) *Error {
-	return maker.newRequestDataLoss
+	return NewRequestDataLoss
 }
 
 // PermissionDenied is used when a user's identity has been verified (authenticated), but the user does not have the
@@ -102,7 +102,7 @@ func (invalidArgIssue) DataLoss() func(opts ErrorInfoOptions) *Error {
 //
 // This case is a "PERMISSION_DENIED" error.
 func (requestIssue) PermissionDenied() func(opts ErrorInfoOptions) *Error {
-	return maker.newPermissionDeniedError
+	return NewPermissionDenied
 }
 
 // Unauthenticated is used when the check for a user's identity fails. For example, when a user attempts to access a
@@ -110,7 +110,7 @@ func (requestIssue) PermissionDenied() func(opts ErrorInfoOptions) *Error {
 //
 // This case is an "UNAUTHENTICATED" error.
 func (requestIssue) Unauthenticated() func(opts ErrorInfoOptions) *Error {
-	return maker.newUnauthenticatedError
+	return NewUnauthenticated
 }
 
 // ServerDataLoss is used when the server encounters an issue that results in data loss.
@@ -126,7 +126,7 @@ func (requestIssue) Unauthenticated() func(opts ErrorInfoOptions) *Error {
 //
 // This case is a "DATA_LOSS" error.
 func (serverIssue) ServerDataLoss() func(err error) *Error {
-	return maker.newServerDataLoss
+	return NewServerDataLoss
 }
 
 // PreconditionFailed is used when a request fails because a precondition for the operation was not met.
@@ -145,7 +145,7 @@ type precondFailureIssue struct{}
 //
 // This case is a "FAILED_PRECONDITION" error.
 func (precondFailureIssue) Other() func(subject, typ, description string) *Error {
-	return maker.newPreconditionFailure
+	return NewPreconditionFailure
 }
 
 // Aborted is a specialized form of precondition failure and is used to indicate that an operation was aborted,
@@ -162,7 +162,7 @@ func (precondFailureIssue) Other() func(subject, typ, description string) *Error
 //
 // This case is an "ABORTED" error.
 func (precondFailureIssue) Aborted() func(opts ErrorInfoOptions) *Error {
-	return maker.newAborted
+	return NewAborted
 }
 
 // AlreadyExists is a specialized form of precondition failure and is used when an attempt to create a resource fails
@@ -177,7 +177,7 @@ func (precondFailureIssue) Aborted() func(opts ErrorInfoOptions) *Error {
 //
 // This case is an "ALREADY_EXISTS" error.
 func (precondFailureIssue) AlreadyExists() func(info ResourceInfo) *Error {
-	return maker.newAlreadyExists
+	return NewAlreadyExists
 }
 
 // ResourceExhausted is a specialized form of precondition failure and is used when a resource has been exhausted,
@@ -195,7 +195,7 @@ type resourceExhaustedIssue struct{}
 //
 // This case is a "RESOURCE_EXHAUSTED" error.
 func (resourceExhaustedIssue) Other() func(opts ErrorInfoOptions) *Error {
-	return maker.newResourceExhausted
+	return NewResourceExhausted
 }
 
 // QuotaFailure is a specialized form of resource exhausted error and is used when an alloted quota or limit
@@ -208,7 +208,7 @@ func (resourceExhaustedIssue) Other() func(opts ErrorInfoOptions) *Error {
 //
 // This case is a "RESOURCE_EXHAUSTED" error.
 func (resourceExhaustedIssue) QuotaFailure() func(subject, description string) *Error {
-	return maker.newQuotaFailure
+	return NewQuotaFailure
 }
 
 // Unknown is used for errors that are unknown or that do not fit any other standard error categories. This is a
@@ -222,7 +222,7 @@ func (resourceExhaustedIssue) QuotaFailure() func(subject, description string) *
 //
 // This case is an "UNKNOWN" error.
 func (serverIssue) Unknown() func(err error) *Error {
-	return maker.newUnknown
+	return NewUnknown
 }
 
 // Internal is used when the server encounters an unexpected condition that prevents it from fulfilling the request.
@@ -238,7 +238,7 @@ func (serverIssue) Unknown() func(err error) *Error {
 //
 // This case is an "INTERNAL" error.
 func (serverIssue) Internal() func(err error) *Error {
-	return maker.newInternalError
+	return NewInternal
 }
 
 // NotImplemented is used when an operation is not supported by the server. This can be due to the feature not being
@@ -254,7 +254,7 @@ func (serverIssue) Internal() func(err error) *Error {
 //
 // This case is a "NOT_IMPLEMENTED" error.
 func (serverIssue) NotImplemented() func() *Error {
-	return maker.newNotImplemented
+	return NewNotImplemented
 }
 
 // Unavailable is used when the whole server is currently unavailable, not just the requested operation.
@@ -270,7 +270,7 @@ func (serverIssue) NotImplemented() func() *Error {
 //
 // This case is an "UNAVAILABLE" error.
 func (serverIssue) Unavailable() func(err error) *Error {
-	return maker.newUnavailable
+	return NewUnavailable
 }
 
 // DeadlineExceeded is used when the request took too long to complete and has exceeded the time allocated for it.
@@ -286,5 +286,5 @@ func (serverIssue) Unavailable() func(err error) *Error {
 //
 // This case is a "DEADLINE_EXCEEDED" error.
 func (serverIssue) DeadlineExceeded() func() *Error {
-	return maker.newDeadlineExceeded
+	return NewDeadlineExceeded
 }
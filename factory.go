@@ -2,6 +2,7 @@ package xerror
 
 import (
 	"errors"
+	"fmt"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -9,6 +10,9 @@ import (
 
 var ErrFailedToAddErrorDetails = errors.New("failed to add error details")
 
+// ErrInvalidSentinel is what NewSentinel panics with if the domain or the reason is empty.
+var ErrInvalidSentinel = errors.New("sentinel domain and reason must not be empty")
+
 const (
 	msgInvalidArg           = "one request arguments was invalid"
 	msgInvalidArgs          = "one or more request arguments were invalid"
@@ -74,15 +78,14 @@ type BadRequestViolation struct {
 	//     first `emailAddresses` message
 	//   - `emailAddresses[3].type[2]` for a violation in the second `type`
 	//     value in the third `emailAddresses` message.
+	//
+	// Use FieldPath to build the value instead of concatenating strings.
 	Field string
 	// Description is a description of why the request element is bad.
 	Description string
 }
 
 func (f factory) newInvalidArgument(field, description string) *Error {
-	// TODO(tobbstr): Add a function that accepts a the request object field and then it returns the field name.
-	// Ex. Instead of the user having to construct the field name such as "person.ownedDogs[1].name", they can
-	// pass the object and the function returns the field name.
 	return f.newBadRequest(msgInvalidArg, BadRequestViolation{Field: field, Description: description})
 }
 
@@ -110,7 +113,7 @@ type PreconditionViolation struct {
 func (f factory) newPreconditionFailure(subject, typ, description string) *Error {
 	e := &Error{
 		status:   *status.New(codes.FailedPrecondition, msgPreconditionFailure),
-		logLevel: LogLevelWarn,
+		logLevel: codeInfos[codes.FailedPrecondition].LogLevel,
 	}
 	_ = e.AddPreconditionViolations([]PreconditionViolation{{Description: description, Subject: subject, Typ: typ}})
 	return e
@@ -119,7 +122,7 @@ func (f factory) newPreconditionFailure(subject, typ, description string) *Error
 func (f factory) newPreconditionFailures(violations []PreconditionViolation) *Error {
 	e := &Error{
 		status:   *status.New(codes.FailedPrecondition, msgPreconditionFailures),
-		logLevel: LogLevelWarn,
+		logLevel: codeInfos[codes.FailedPrecondition].LogLevel,
 	}
 
 	_ = e.AddPreconditionViolations(violations)
@@ -129,7 +132,7 @@ func (f factory) newPreconditionFailures(violations []PreconditionViolation) *Er
 func (f factory) newOutOfRangeError(field, description string) *Error {
 	e := &Error{
 		status:   *status.New(codes.OutOfRange, msgOutOfRange),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.OutOfRange].LogLevel,
 	}
 	_ = e.AddBadRequestViolations([]BadRequestViolation{{Field: field, Description: description}})
 	return e
@@ -138,7 +141,7 @@ func (f factory) newOutOfRangeError(field, description string) *Error {
 func (f factory) newOutOfRangeErrors(violations []BadRequestViolation) *Error {
 	e := &Error{
 		status:   *status.New(codes.OutOfRange, msgOutOfRangeErrors),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.OutOfRange].LogLevel,
 	}
 	_ = e.AddBadRequestViolations(violations)
 	return e
@@ -169,11 +172,11 @@ type ErrorInfoOptions struct {
 }
 
 func (f factory) newUnauthenticatedError(opts ErrorInfoOptions) *Error {
-	return f.newErrorInfoError(codes.Unauthenticated, LogLevelInfo, opts)
+	return f.newErrorInfoError(codes.Unauthenticated, codeInfos[codes.Unauthenticated].LogLevel, opts)
 }
 
 func (f factory) newPermissionDeniedError(opts ErrorInfoOptions) *Error { // nolint:unparam
-	e := f.newErrorInfoError(codes.PermissionDenied, LogLevelInfo, opts)
+	e := f.newErrorInfoError(codes.PermissionDenied, codeInfos[codes.PermissionDenied].LogLevel, opts)
 	return e
 }
 
@@ -199,10 +202,9 @@ type ResourceInfo struct {
 }
 
 func (_ factory) newNotFound(info ResourceInfo) *Error {
-	const msg = "requested resource not found"
 	e := &Error{
-		status:   *status.New(codes.NotFound, msg),
-		logLevel: LogLevelInfo,
+		status:   *status.New(codes.NotFound, codeInfos[codes.NotFound].DefaultMessage),
+		logLevel: codeInfos[codes.NotFound].LogLevel,
 	}
 
 	_ = e.AddResourceInfos([]ResourceInfo{info})
@@ -213,7 +215,7 @@ func (_ factory) newBatchNotFound(infos []ResourceInfo) *Error {
 	const msg = "requested resources not found"
 	e := &Error{
 		status:   *status.New(codes.NotFound, msg),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.NotFound].LogLevel,
 	}
 
 	_ = e.AddResourceInfos(infos)
@@ -221,14 +223,13 @@ func (_ factory) newBatchNotFound(infos []ResourceInfo) *Error {
 }
 
 func (f factory) newAborted(opts ErrorInfoOptions) *Error {
-	return f.newErrorInfoError(codes.Aborted, LogLevelWarn, opts)
+	return f.newErrorInfoError(codes.Aborted, codeInfos[codes.Aborted].LogLevel, opts)
 }
 
 func (f factory) newAlreadyExists(info ResourceInfo) *Error {
-	const msg = "resource already exists"
 	e := &Error{
-		status:   *status.New(codes.AlreadyExists, msg),
-		logLevel: LogLevelInfo,
+		status:   *status.New(codes.AlreadyExists, codeInfos[codes.AlreadyExists].DefaultMessage),
+		logLevel: codeInfos[codes.AlreadyExists].LogLevel,
 	}
 
 	_ = e.AddResourceInfos([]ResourceInfo{info})
@@ -239,7 +240,7 @@ func (_ factory) newAlreadyExistsBatch(infos []ResourceInfo) *Error {
 	const msg = "resources already exist"
 	e := &Error{
 		status:   *status.New(codes.AlreadyExists, msg),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.AlreadyExists].LogLevel,
 	}
 	_ = e.AddResourceInfos(infos)
 	return e
@@ -247,8 +248,8 @@ func (_ factory) newAlreadyExistsBatch(infos []ResourceInfo) *Error {
 
 func (_ factory) newQuotaFailure(subject, description string) *Error {
 	e := &Error{
-		status:   *status.New(codes.ResourceExhausted, "the request cannot be completed because the quota has been exhausted"),
-		logLevel: LogLevelInfo,
+		status:   *status.New(codes.ResourceExhausted, codeInfos[codes.ResourceExhausted].DefaultMessage),
+		logLevel: codeInfos[codes.ResourceExhausted].LogLevel,
 	}
 
 	_ = e.AddQuotaViolations([]QuotaViolation{{Subject: subject, Description: description}})
@@ -257,8 +258,8 @@ func (_ factory) newQuotaFailure(subject, description string) *Error {
 
 func (_ factory) newQuotaFailureBatch(violations []QuotaViolation) *Error {
 	e := &Error{
-		status:   *status.New(codes.ResourceExhausted, "the request cannot be completed because the quota has been exhausted"),
-		logLevel: LogLevelInfo,
+		status:   *status.New(codes.ResourceExhausted, codeInfos[codes.ResourceExhausted].DefaultMessage),
+		logLevel: codeInfos[codes.ResourceExhausted].LogLevel,
 	}
 
 	_ = e.AddQuotaViolations(violations)
@@ -270,9 +271,9 @@ func (_ factory) newResourceExhausted(opts ErrorInfoOptions) *Error {
 }
 
 func (_ factory) newCancelledError() *Error {
-	const msg = "request cancelled by the client"
 	e := &Error{
-		status: *status.New(codes.Canceled, msg),
+		status:   *status.New(codes.Canceled, codeInfos[codes.Canceled].DefaultMessage),
+		logLevel: codeInfos[codes.Canceled].LogLevel,
 	}
 	return e
 }
@@ -280,11 +281,11 @@ func (_ factory) newCancelledError() *Error {
 func (f factory) newServerDataLoss(err error) *Error {
 	var msg string
 	if err == nil {
-		msg = "server data loss"
+		msg = codeInfos[codes.DataLoss].DefaultMessage
 	} else {
 		msg = err.Error()
 	}
-	return f.newErrorWithDetailsHidden(codes.DataLoss, msg, LogLevelError)
+	return f.newErrorWithDetailsHidden(codes.DataLoss, msg, codeInfos[codes.DataLoss].LogLevel)
 }
 
 func (_ factory) newRequestDataLoss(opts ErrorInfoOptions) *Error {
@@ -294,28 +295,27 @@ func (_ factory) newRequestDataLoss(opts ErrorInfoOptions) *Error {
 func (f factory) newUnknown(err error) *Error {
 	var msg string
 	if err == nil {
-		msg = "something unknown happened"
+		msg = codeInfos[codes.Unknown].DefaultMessage
 	} else {
 		msg = err.Error()
 	}
-	return f.newErrorWithDetailsHidden(codes.Unknown, msg, LogLevelError)
+	return f.newErrorWithDetailsHidden(codes.Unknown, msg, codeInfos[codes.Unknown].LogLevel)
 }
 
 func (f factory) newInternalError(err error) *Error {
 	var msg string
 	if err == nil {
-		msg = "an internal server error happened"
+		msg = codeInfos[codes.Internal].DefaultMessage
 	} else {
 		msg = err.Error()
 	}
-	return f.newErrorWithDetailsHidden(codes.Internal, msg, LogLevelError)
+	return f.newErrorWithDetailsHidden(codes.Internal, msg, codeInfos[codes.Internal].LogLevel)
 }
 
 func (f factory) newNotImplemented() *Error {
-	const msg = "not implemented"
 	e := &Error{
-		status:   *status.New(codes.Unimplemented, msg),
-		logLevel: LogLevelInfo,
+		status:   *status.New(codes.Unimplemented, codeInfos[codes.Unimplemented].DefaultMessage),
+		logLevel: codeInfos[codes.Unimplemented].LogLevel,
 	}
 	return e
 }
@@ -323,27 +323,41 @@ func (f factory) newNotImplemented() *Error {
 func (f factory) newUnavailable(err error) *Error {
 	var msg string
 	if err == nil {
-		msg = "the operation is currently unavailable"
+		msg = codeInfos[codes.Unavailable].DefaultMessage
 	} else {
 		msg = err.Error()
 	}
-	return f.newErrorWithDetailsHidden(codes.Unavailable, msg, LogLevelInfo)
+	return f.newErrorWithDetailsHidden(codes.Unavailable, msg, codeInfos[codes.Unavailable].LogLevel)
 }
 
 func (f factory) newDeadlineExceeded() *Error {
 	return f.newErrorWithDetailsHidden(
 		codes.DeadlineExceeded,
-		"the operation timed out (it might have succeeded though)",
-		LogLevelWarn,
+		codeInfos[codes.DeadlineExceeded].DefaultMessage,
+		codeInfos[codes.DeadlineExceeded].LogLevel,
 	)
 }
 
+func (_ factory) newSentinel(code codes.Code, domain, reason string) *Error {
+	if domain == "" || reason == "" {
+		// The domain isn't defaulted to the one passed to Init, since sentinels are usually created before it's called
+		panic(fmt.Errorf("domain %q, reason %q: %w", domain, reason, ErrInvalidSentinel))
+	}
+	e := &Error{
+		status:   *status.New(code, reason),
+		logLevel: CodeInfoOf(code).LogLevel,
+	}
+	_ = e.SetErrorInfo(domain, reason, nil)
+	e.sentinel = true
+	return e
+}
+
 /* ------------------------- Factory helper methods ------------------------- */
 
 func (_ factory) newBadRequest(msg string, violation BadRequestViolation) *Error {
 	e := &Error{
 		status:   *status.New(codes.InvalidArgument, msg),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.InvalidArgument].LogLevel,
 	}
 
 	_ = e.AddBadRequestViolations([]BadRequestViolation{violation})
@@ -353,7 +367,7 @@ func (_ factory) newBadRequest(msg string, violation BadRequestViolation) *Error
 func (_ factory) newBatchBadRequest(msg string, violations []BadRequestViolation) *Error {
 	e := &Error{
 		status:   *status.New(codes.InvalidArgument, msg),
-		logLevel: LogLevelInfo,
+		logLevel: codeInfos[codes.InvalidArgument].LogLevel,
 	}
 	_ = e.AddBadRequestViolations(violations)
 	return e
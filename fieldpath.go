@@ -0,0 +1,218 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrInvalidFieldPath is returned by FieldPath.Validate when the path doesn't lead to a field of the message.
+var ErrInvalidFieldPath = errors.New("invalid field path")
+
+// FieldPath is a builder of the paths used in the Field of a BadRequestViolation. It renders the path in either proto
+// (snake_case) or JSON (lowerCamelCase) naming, so callers don't have to concatenate strings.
+//
+// A FieldPath is immutable, so every method returns a new FieldPath and paths can safely share a common prefix.
+//
+// Ex.
+//
+//	path := xerror.Field("email_addresses").Index(3).Field("type").Index(2)
+//	path.Proto() // email_addresses[3].type[2]
+//	path.JSON()  // emailAddresses[3].type[2]
+type FieldPath struct {
+	segments []fieldPathSegment
+}
+
+type fieldPathSegment struct {
+	// name is the field name as given by the caller, in either snake_case or camelCase.
+	name string
+	// desc is the field descriptor, if the segment was derived from one.
+	desc protoreflect.FieldDescriptor
+	// subscripts are the rendered list indexes and map keys of the field, ex. `[3]` or `["key"]`.
+	subscripts []string
+}
+
+// Field returns a new FieldPath starting with the field. The name can be given in either snake_case or camelCase.
+func Field(name string) FieldPath {
+	return FieldPath{}.Field(name)
+}
+
+// FieldPathFrom returns a new FieldPath from a path of field descriptors. The names are taken from the descriptors,
+// which means that the JSON naming honours any custom json_name options.
+func FieldPathFrom(fields ...protoreflect.FieldDescriptor) FieldPath {
+	var path FieldPath
+	for _, fd := range fields {
+		path = path.FieldDescriptor(fd)
+	}
+	return path
+}
+
+// Field returns a copy of the path with the field appended. The name can be given in either snake_case or camelCase.
+func (p FieldPath) Field(name string) FieldPath {
+	return p.appendSegment(fieldPathSegment{name: name})
+}
+
+// FieldDescriptor returns a copy of the path with the field described by the descriptor appended.
+func (p FieldPath) FieldDescriptor(fd protoreflect.FieldDescriptor) FieldPath {
+	return p.appendSegment(fieldPathSegment{name: string(fd.Name()), desc: fd})
+}
+
+// Index returns a copy of the path where the last field is indexed, ex. `type[2]`. It is used for repeated fields.
+func (p FieldPath) Index(i int) FieldPath {
+	return p.appendSubscript("[" + strconv.Itoa(i) + "]")
+}
+
+// Key returns a copy of the path where the last field is subscripted with a map key. String keys are quoted, ex.
+// `labels["team"]`, while other keys are formatted as-is, ex. `counts[3]`.
+func (p FieldPath) Key(key any) FieldPath {
+	if s, ok := key.(string); ok {
+		return p.appendSubscript("[" + strconv.Quote(s) + "]")
+	}
+	return p.appendSubscript(fmt.Sprintf("[%v]", key))
+}
+
+// Proto returns the path using proto field names, ex. `email_addresses[3].type[2]`.
+func (p FieldPath) Proto() string {
+	return p.render(func(s fieldPathSegment) string {
+		if s.desc != nil {
+			return string(s.desc.Name())
+		}
+		return snakeCaseFrom(s.name)
+	})
+}
+
+// JSON returns the path using JSON field names, ex. `emailAddresses[3].type[2]`.
+func (p FieldPath) JSON() string {
+	return p.render(func(s fieldPathSegment) string {
+		if s.desc != nil {
+			return s.desc.JSONName()
+		}
+		return lowerCamelCaseFrom(snakeCaseFrom(s.name))
+	})
+}
+
+// String returns the path using proto field names. See Proto.
+func (p FieldPath) String() string {
+	return p.Proto()
+}
+
+// Validate checks that the path leads to a field in messages described by md. Fields may be given by either their
+// proto or JSON names. Indexes are only allowed on repeated fields, and keys only on map fields.
+func (p FieldPath) Validate(md protoreflect.MessageDescriptor) error {
+	if len(p.segments) == 0 {
+		return fmt.Errorf("empty path: %w", ErrInvalidFieldPath)
+	}
+	for i, s := range p.segments {
+		if md == nil {
+			return fmt.Errorf("%q is not a message field, so it has no field %q: %w", p.segments[i-1].name, s.name, ErrInvalidFieldPath)
+		}
+		fd := md.Fields().ByName(protoreflect.Name(snakeCaseFrom(s.name)))
+		if fd == nil {
+			fd = md.Fields().ByJSONName(s.name)
+		}
+		if fd == nil {
+			return fmt.Errorf("message %s has no field %q: %w", md.FullName(), s.name, ErrInvalidFieldPath)
+		}
+		if len(s.subscripts) > 1 {
+			return fmt.Errorf("field %q has more than one subscript: %w", s.name, ErrInvalidFieldPath)
+		}
+		subscripted := len(s.subscripts) == 1
+		switch {
+		case fd.IsMap():
+			if subscripted && strings.HasPrefix(s.subscripts[0], `["`) != (fd.MapKey().Kind() == protoreflect.StringKind) {
+				return fmt.Errorf("field %q has a key of the wrong kind: %w", s.name, ErrInvalidFieldPath)
+			}
+			md = fd.MapValue().Message()
+		case fd.IsList():
+			if subscripted && strings.HasPrefix(s.subscripts[0], `["`) {
+				return fmt.Errorf("field %q is repeated, so it can't have a key: %w", s.name, ErrInvalidFieldPath)
+			}
+			md = fd.Message()
+		default:
+			if subscripted {
+				return fmt.Errorf("field %q is neither repeated nor a map, so it can't be subscripted: %w", s.name, ErrInvalidFieldPath)
+			}
+			md = fd.Message()
+		}
+		// A repeated or map field must be subscripted to reach the fields of its elements
+		if (fd.IsList() || fd.IsMap()) && !subscripted && i < len(p.segments)-1 {
+			return fmt.Errorf("field %q must be subscripted to reach its elements: %w", s.name, ErrInvalidFieldPath)
+		}
+	}
+	return nil
+}
+
+func (p FieldPath) appendSegment(s fieldPathSegment) FieldPath {
+	segments := make([]fieldPathSegment, len(p.segments), len(p.segments)+1)
+	copy(segments, p.segments)
+	return FieldPath{segments: append(segments, s)}
+}
+
+func (p FieldPath) appendSubscript(subscript string) FieldPath {
+	if len(p.segments) == 0 {
+		return p
+	}
+	segments := make([]fieldPathSegment, len(p.segments))
+	copy(segments, p.segments)
+	last := &segments[len(segments)-1]
+	last.subscripts = append(last.subscripts[:len(last.subscripts):len(last.subscripts)], subscript)
+	return FieldPath{segments: segments}
+}
+
+func (p FieldPath) render(name func(s fieldPathSegment) string) string {
+	var b strings.Builder
+	for i, s := range p.segments {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(name(s))
+		for _, subscript := range s.subscripts {
+			b.WriteString(subscript)
+		}
+	}
+	return b.String()
+}
+
+// lowerCamelCaseFrom converts snake_case names to lowerCamelCase, the same way as protobuf derives JSON names.
+func lowerCamelCaseFrom(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	upperNext := false
+	for i, r := range s {
+		switch {
+		case r == '_':
+			upperNext = true
+		case upperNext:
+			b.WriteRune(unicode.ToUpper(r))
+			upperNext = false
+		case i == 0:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// snakeCaseFrom converts UpperCamelCase and lowerCamelCase names to snake_case. Acronyms are kept together, ex.
+// "UserID" becomes "user_id". Names that are already in snake_case are returned unchanged.
+func snakeCaseFrom(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prev != '_' && (unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower)) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
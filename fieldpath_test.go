@@ -0,0 +1,162 @@
+package xerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// createContactRequestDescriptor returns the descriptor of the message used as an example in the documentation of
+// BadRequestViolation.Field, extended with a map field.
+func createContactRequestDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	var (
+		optional = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+		repeated = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		str      = descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+		enum     = descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum()
+		message  = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+	)
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("example/contact.proto"),
+		Package: proto.String("example"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("CreateContactRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("full_name"), JsonName: proto.String("fullName"), Number: proto.Int32(1), Label: optional, Type: str},
+				{Name: proto.String("email_addresses"), JsonName: proto.String("emailAddresses"), Number: proto.Int32(2), Label: repeated, Type: message, TypeName: proto.String(".example.CreateContactRequest.EmailAddress")},
+				{Name: proto.String("labels"), JsonName: proto.String("labels"), Number: proto.Int32(3), Label: repeated, Type: message, TypeName: proto.String(".example.CreateContactRequest.LabelsEntry")},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				{
+					Name: proto.String("EmailAddress"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("email"), JsonName: proto.String("email"), Number: proto.Int32(1), Label: optional, Type: str},
+						{Name: proto.String("type"), JsonName: proto.String("type"), Number: proto.Int32(2), Label: repeated, Type: enum, TypeName: proto.String(".example.CreateContactRequest.EmailAddress.Type")},
+					},
+					EnumType: []*descriptorpb.EnumDescriptorProto{{
+						Name: proto.String("Type"),
+						Value: []*descriptorpb.EnumValueDescriptorProto{
+							{Name: proto.String("TYPE_UNSPECIFIED"), Number: proto.Int32(0)},
+							{Name: proto.String("HOME"), Number: proto.Int32(1)},
+							{Name: proto.String("WORK"), Number: proto.Int32(2)},
+						},
+					}},
+				},
+				{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: str},
+						{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: str},
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	require.NoError(t, err)
+	return fd.Messages().ByName("CreateContactRequest")
+}
+
+func TestFieldPath(t *testing.T) {
+	md := createContactRequestDescriptor(t)
+	emailAddresses := md.Fields().ByName("email_addresses")
+
+	type want struct {
+		proto       string
+		json        string
+		validateErr bool
+	}
+	tests := []struct {
+		name string
+		path FieldPath
+		want want
+	}{
+		{
+			name: "single field",
+			path: Field("full_name"),
+			want: want{proto: "full_name", json: "fullName"},
+		},
+		{
+			name: "single field in camel case",
+			path: Field("fullName"),
+			want: want{proto: "full_name", json: "fullName"},
+		},
+		{
+			name: "nested field in repeated message",
+			path: Field("email_addresses").Index(1).Field("email"),
+			want: want{proto: "email_addresses[1].email", json: "emailAddresses[1].email"},
+		},
+		{
+			name: "repeated field in repeated message",
+			path: Field("email_addresses").Index(3).Field("type").Index(2),
+			want: want{proto: "email_addresses[3].type[2]", json: "emailAddresses[3].type[2]"},
+		},
+		{
+			name: "map field",
+			path: Field("labels").Key("team"),
+			want: want{proto: `labels["team"]`, json: `labels["team"]`},
+		},
+		{
+			name: "derived from field descriptors",
+			path: FieldPathFrom(emailAddresses).Index(3).FieldDescriptor(emailAddresses.Message().Fields().ByName("type")).Index(2),
+			want: want{proto: "email_addresses[3].type[2]", json: "emailAddresses[3].type[2]"},
+		},
+		{
+			name: "unknown field",
+			path: Field("phone_number"),
+			want: want{proto: "phone_number", json: "phoneNumber", validateErr: true},
+		},
+		{
+			name: "index on non-repeated field",
+			path: Field("full_name").Index(1),
+			want: want{proto: "full_name[1]", json: "fullName[1]", validateErr: true},
+		},
+		{
+			name: "key on repeated field",
+			path: Field("email_addresses").Key("home"),
+			want: want{proto: `email_addresses["home"]`, json: `emailAddresses["home"]`, validateErr: true},
+		},
+		{
+			name: "repeated field without index",
+			path: Field("email_addresses").Field("email"),
+			want: want{proto: "email_addresses.email", json: "emailAddresses.email", validateErr: true},
+		},
+		{
+			name: "field of scalar",
+			path: Field("full_name").Field("first"),
+			want: want{proto: "full_name.first", json: "fullName.first", validateErr: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.proto, tt.path.Proto())
+			require.Equal(tt.want.json, tt.path.JSON())
+			err := tt.path.Validate(md)
+			if tt.want.validateErr {
+				require.True(errors.Is(err, ErrInvalidFieldPath), "got error: %v", err)
+				return
+			}
+			require.NoError(err)
+		})
+	}
+}
+
+func TestFieldPath_IsImmutable(t *testing.T) {
+	base := Field("email_addresses").Index(1)
+	email := base.Field("email")
+	typ := base.Field("type").Index(2)
+
+	require.Equal(t, "email_addresses[1]", base.Proto())
+	require.Equal(t, "email_addresses[1].email", email.Proto())
+	require.Equal(t, "email_addresses[1].type[2]", typ.Proto())
+}
@@ -0,0 +1,117 @@
+package xerror
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MappingAction is what a MappingPolicy does with an error returned by an upstream service.
+type MappingAction uint8
+
+const (
+	// MappingActionPassThrough returns the upstream error to our own callers as is.
+	MappingActionPassThrough MappingAction = iota
+	// MappingActionConvert changes the status code of the upstream error, see MappingRule.ConvertTo. Its message and
+	// details are kept, while its log level is reset to the one of the new status code, see CodeInfo.LogLevel.
+	MappingActionConvert
+	// MappingActionHide replaces the upstream error with an Internal error, whose details are hidden. The status of the
+	// upstream error is kept in the debug info detail, so that it's logged but not returned to our own callers.
+	MappingActionHide
+)
+
+// MappingRule matches errors returned by an upstream service and declares what to do with them. All of its non-zero
+// matching fields must match for the rule to match.
+type MappingRule struct {
+	// Code matches errors with the status code. If it's codes.OK, then errors with any status code match.
+	Code codes.Code
+	// Domain matches errors whose error info detail has the domain. If it's empty, then errors with any domain match,
+	// including errors without an error info detail.
+	Domain string
+	// Reason matches errors whose error info detail has the reason. If it's empty, then errors with any reason match.
+	Reason string
+	// Action is what to do with matching errors.
+	Action MappingAction
+	// ConvertTo is the status code matching errors are converted to, if Action is MappingActionConvert.
+	ConvertTo codes.Code
+}
+
+// MappingPolicy declares how errors returned by an upstream service are re-exposed to our own callers. The rules are
+// evaluated in order, and the first matching rule is applied. If no rule matches, then Default is applied.
+//
+// Ex.
+//
+//	policy := xerror.MappingPolicy{
+//	  Rules: []xerror.MappingRule{
+//	    {Code: codes.NotFound, Action: xerror.MappingActionConvert, ConvertTo: codes.FailedPrecondition},
+//	    {Domain: order.Domain, Reason: order.ReasonOutOfStock, Action: xerror.MappingActionPassThrough},
+//	    {Code: codes.InvalidArgument, Action: xerror.MappingActionHide},
+//	  },
+//	}
+type MappingPolicy struct {
+	// Rules are the rules that are evaluated in order.
+	Rules []MappingRule
+	// Default is the action applied to errors that don't match any rule. It can't be MappingActionConvert, since there
+	// is no status code to convert to, in which case the errors are passed through.
+	Default MappingAction
+}
+
+// Apply returns the error as declared by the policy. If err isn't an xerror, it's converted using From. If err is nil,
+// then it returns nil.
+func (p MappingPolicy) Apply(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	xerr := From(err)
+	for _, rule := range p.Rules {
+		if rule.matches(xerr) {
+			return applyMappingAction(xerr, rule.Action, rule.ConvertTo)
+		}
+	}
+	if p.Default == MappingActionConvert {
+		return xerr
+	}
+	return applyMappingAction(xerr, p.Default, codes.OK)
+}
+
+func (r MappingRule) matches(xerr *Error) bool {
+	if r.Code != codes.OK && r.Code != xerr.StatusCode() {
+		return false
+	}
+	if r.Domain == "" && r.Reason == "" {
+		return true
+	}
+	info := xerr.ErrorInfo()
+	if !info.Valid {
+		return false
+	}
+	return (r.Domain == "" || r.Domain == info.Value.Domain) && (r.Reason == "" || r.Reason == info.Value.Reason)
+}
+
+func applyMappingAction(xerr *Error, action MappingAction, convertTo codes.Code) *Error {
+	switch action {
+	case MappingActionConvert:
+		st := xerr.StatusProto()
+		st.Code = int32(convertTo)
+		xerr.status = *status.FromProto(st)
+		xerr.logLevel = codeInfos[convertTo].LogLevel
+		return xerr
+	case MappingActionHide:
+		hidden := maker.newInternalError(nil).
+			SetDebugInfo(fmt.Sprintf("upstream error: code = %s desc = %s", xerr.StatusCode(), xerr.StatusMessage()), nil).
+			AddVars(xerr.runtimeState...)
+		hidden.traceContext = xerr.traceContext
+		// The provenance is kept, since it's removed along with the other hidden details before responding
+		if provenance, err := xerr.findProvenance(); err == nil {
+			st, err := hidden.status.WithDetails(provenance)
+			if err != nil {
+				panic(fmt.Errorf("%v: %w", err, ErrFailedToAddErrorDetails))
+			}
+			hidden.status = *st
+		}
+		return hidden
+	default:
+		return xerr
+	}
+}
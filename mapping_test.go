@@ -0,0 +1,113 @@
+package xerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMappingPolicy_Apply(t *testing.T) {
+	Init("myservice.example.com")
+	policy := MappingPolicy{
+		Rules: []MappingRule{
+			{Domain: "order.example.com", Reason: "OUT_OF_STOCK", Action: MappingActionPassThrough},
+			{Code: codes.NotFound, Action: MappingActionConvert, ConvertTo: codes.FailedPrecondition},
+			{Domain: "order.example.com", Action: MappingActionHide},
+		},
+	}
+	outOfStock := func() *Error {
+		return NewResourceExhausted(ErrorInfoOptions{Error: errors.New("out of stock"), Reason: "OUT_OF_STOCK"}).
+			SetErrorInfo("order.example.com", "OUT_OF_STOCK", nil)
+	}
+
+	type want struct {
+		code          codes.Code
+		message       string
+		detailsHidden bool
+		debugInfo     string
+		runtimeState  []Var
+		logLevel      LogLevel
+	}
+	tests := []struct {
+		name   string
+		policy MappingPolicy
+		err    error
+		want   want
+	}{
+		{
+			name:   "passes through matching domain and reason",
+			policy: policy,
+			err:    outOfStock(),
+			want:   want{code: codes.ResourceExhausted, message: "out of stock", logLevel: LogLevelWarn},
+		},
+		{
+			name:   "converts matching code",
+			policy: policy,
+			err:    NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"}),
+			want: want{
+				code:     codes.FailedPrecondition,
+				message:  "requested resource not found",
+				logLevel: LogLevelWarn,
+			},
+		},
+		{
+			name:   "hides matching domain",
+			policy: policy,
+			err: NewAborted(ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+				SetErrorInfo("order.example.com", "VERSION_MISMATCH", nil).
+				AddVar("order_id", "order-1"),
+			want: want{
+				code:          codes.Internal,
+				message:       "an internal server error happened",
+				detailsHidden: true,
+				debugInfo:     "upstream error: code = Aborted desc = conflict",
+				runtimeState:  []Var{{Name: "order_id", Value: "order-1"}},
+				logLevel:      LogLevelError,
+			},
+		},
+		{
+			name:   "applies default to unmatched error",
+			policy: MappingPolicy{Default: MappingActionHide},
+			err:    NewUnavailable(errors.New("connection refused")),
+			want: want{
+				code:          codes.Internal,
+				message:       "an internal server error happened",
+				detailsHidden: true,
+				debugInfo:     "upstream error: code = Unavailable desc = connection refused",
+				logLevel:      LogLevelError,
+			},
+		},
+		{
+			name:   "passes through unmatched error by default",
+			policy: policy,
+			err:    NewUnavailable(errors.New("connection refused")),
+			want: want{
+				code:          codes.Unavailable,
+				message:       "connection refused",
+				detailsHidden: true,
+				logLevel:      LogLevelInfo,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := tt.policy.Apply(tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.code, got.StatusCode())
+			require.Equal(tt.want.message, got.StatusMessage())
+			require.Equal(tt.want.detailsHidden, got.IsDetailsHidden())
+			require.Equal(tt.want.debugInfo, got.DebugInfo().Value.Detail)
+			require.Equal(tt.want.runtimeState, got.RuntimeState())
+			require.Equal(tt.want.logLevel, got.LogLevel())
+		})
+	}
+}
+
+func TestMappingPolicy_ApplyNil(t *testing.T) {
+	require.Nil(t, MappingPolicy{}.Apply(nil))
+}
@@ -0,0 +1,163 @@
+package xerror
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codePrecedence is the order in which the status code of an aggregate error is picked from the codes of its errors.
+// Errors that won't go away by retrying come before transient ones, since retrying is pointless if any of the errors
+// is permanent. Among the permanent ones, bugs and data loss come first, then the caller's lack of access, then bad
+// requests, and finally the state of the system.
+var codePrecedence = []codes.Code{
+	codes.DataLoss,
+	codes.Internal,
+	codes.Unknown,
+	codes.Unimplemented,
+	codes.Unauthenticated,
+	codes.PermissionDenied,
+	codes.InvalidArgument,
+	codes.OutOfRange,
+	codes.FailedPrecondition,
+	codes.NotFound,
+	codes.AlreadyExists,
+	codes.ResourceExhausted,
+	codes.Aborted,
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.Canceled,
+}
+
+// MultiError aggregates several errors, for example the errors returned when fanning out to several backends
+// concurrently. It is created using the Join function, and it is converted to a single Error by XError or From.
+type MultiError struct {
+	errs []error
+}
+
+// Join returns an error that aggregates the errors. Nil errors are discarded, and if all errors are nil, Join returns
+// nil. It works like errors.Join, which means that errors.Is and errors.As look through all the aggregated errors.
+//
+// Ex.
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	var userErr, orderErr error
+//	g.Go(func() error { userErr = users.Get(ctx, id); return nil })
+//	g.Go(func() error { orderErr = orders.List(ctx, id); return nil })
+//	_ = g.Wait()
+//	if err := xerror.Join(userErr, orderErr); err != nil {
+//		return err
+//	}
+func Join(errs ...error) error {
+	multi := &MultiError{errs: make([]error, 0, len(errs))}
+	for _, err := range errs {
+		if err != nil {
+			multi.errs = append(multi.errs, err)
+		}
+	}
+	if len(multi.errs) == 0 {
+		return nil
+	}
+	return multi
+}
+
+// Error returns the messages of the aggregated errors, separated by newlines.
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the aggregated errors.
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// XError returns a single Error that represents all the aggregated errors. See From for how it's done.
+func (m *MultiError) XError() *Error {
+	return aggregate(m.errs)
+}
+
+// aggregate converts the errors to a single Error. The status is the one of the error whose code comes first in
+// codePrecedence. The bad request, precondition, quota and resource info details of the other errors with the same
+// code are merged into it, unless their details are hidden. The runtime state of all errors is kept, and the log
+// level is the highest one among the errors. The returned Error is always a new instance.
+func aggregate(errs []error) *Error {
+	xerrs := make([]*Error, 0, len(errs))
+	for _, err := range errs {
+		if xerr := From(err); xerr != nil {
+			xerrs = append(xerrs, xerr)
+		}
+	}
+	switch len(xerrs) {
+	case 0:
+		return nil
+	case 1:
+		// It's cloned, since the Error would otherwise be shared with the aggregated error
+		return xerrs[0].clone()
+	}
+
+	representative := xerrs[0]
+	for _, xerr := range xerrs[1:] {
+		if precedenceOf(xerr.StatusCode()) < precedenceOf(representative.StatusCode()) {
+			representative = xerr
+		}
+	}
+
+	agg := &Error{
+		logLevel:      representative.logLevel,
+		status:        *status.FromProto(representative.StatusProto()),
+		detailsHidden: representative.detailsHidden,
+		traceContext:  representative.traceContext,
+	}
+	for _, xerr := range xerrs {
+		agg.logLevel = max(agg.logLevel, xerr.logLevel)
+		agg.runtimeState = append(agg.runtimeState, xerr.runtimeState...)
+		if xerr == representative || xerr.StatusCode() != representative.StatusCode() || xerr.detailsHidden {
+			continue
+		}
+		if violations := xerr.BadRequestViolations(); len(violations) > 0 {
+			_ = agg.AddBadRequestViolations(violations)
+		}
+		if violations := xerr.PreconditionViolations(); len(violations) > 0 {
+			_ = agg.AddPreconditionViolations(violations)
+		}
+		if violations := xerr.QuotaViolations(); len(violations) > 0 {
+			_ = agg.AddQuotaViolations(violations)
+		}
+		if infos := xerr.ResourceInfos(); len(infos) > 0 {
+			_ = agg.AddResourceInfos(infos)
+		}
+	}
+	return agg
+}
+
+// precedenceOf returns the position of the code in codePrecedence. Codes that aren't in the list, such as OK, come
+// last.
+func precedenceOf(code codes.Code) int {
+	for i, c := range codePrecedence {
+		if c == code {
+			return i
+		}
+	}
+	return len(codePrecedence)
+}
+
+// multiUnwrapper is implemented by errors that aggregate several errors, such as the ones returned by Join and
+// errors.Join.
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+// asMulti returns the aggregated errors of the outermost aggregating error in the chain of err, if there is one.
+func asMulti(err error) ([]error, bool) {
+	var multi multiUnwrapper
+	if !errors.As(err, &multi) {
+		return nil, false
+	}
+	return multi.Unwrap(), true
+}
@@ -0,0 +1,163 @@
+package xerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestJoin(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(Join())
+	require.Nil(Join(nil, nil))
+
+	notFound := NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"})
+	err := Join(nil, notFound, context.Canceled)
+	require.Equal([]error{notFound, context.Canceled}, err.(*MultiError).Unwrap())
+	require.ErrorIs(err, context.Canceled)
+	var xerr *Error
+	require.ErrorAs(err, &xerr)
+	require.Same(notFound, xerr)
+}
+
+func TestFrom_Aggregated(t *testing.T) {
+	type want struct {
+		code                 codes.Code
+		logLevel             LogLevel
+		detailsHidden        bool
+		badRequestViolations []BadRequestViolation
+		resourceInfos        []ResourceInfo
+		runtimeState         []Var
+	}
+	tests := []struct {
+		name string
+		err  func() error
+		want want
+	}{
+		{
+			name: "single error is converted as-is",
+			err: func() error {
+				return Join(NewInvalidArgument("name", "must be set").AddVar("id", 1))
+			},
+			want: want{
+				code:                 codes.InvalidArgument,
+				logLevel:             LogLevelInfo,
+				badRequestViolations: []BadRequestViolation{{Field: "name", Description: "must be set"}},
+				runtimeState:         []Var{{Name: "id", Value: 1}},
+			},
+		},
+		{
+			name: "permanent error takes precedence over transient error",
+			err: func() error {
+				return Join(
+					NewDeadlineExceeded().AddVar("backend", "orders"),
+					NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"}).AddVar("backend", "users"),
+				)
+			},
+			want: want{
+				code:          codes.NotFound,
+				logLevel:      LogLevelWarn,
+				resourceInfos: []ResourceInfo{{ResourceType: "user", ResourceName: "users/1"}},
+				runtimeState:  []Var{{Name: "backend", Value: "orders"}, {Name: "backend", Value: "users"}},
+			},
+		},
+		{
+			name: "details of errors with the same code are merged",
+			err: func() error {
+				return Join(
+					NewInvalidArgument("name", "must be set"),
+					NewUnavailable(errors.New("connection refused")),
+					NewInvalidArgumentBatch([]BadRequestViolation{{Field: "age", Description: "must be positive"}}),
+				)
+			},
+			want: want{
+				code:     codes.InvalidArgument,
+				logLevel: LogLevelInfo,
+				badRequestViolations: []BadRequestViolation{
+					{Field: "name", Description: "must be set"},
+					{Field: "age", Description: "must be positive"},
+				},
+			},
+		},
+		{
+			name: "errors.Join is supported",
+			err: func() error {
+				return fmt.Errorf("fan out: %w", errors.Join(
+					NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"}),
+					NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/2"}),
+				))
+			},
+			want: want{
+				code:     codes.NotFound,
+				logLevel: LogLevelInfo,
+				resourceInfos: []ResourceInfo{
+					{ResourceType: "user", ResourceName: "users/1"},
+					{ResourceType: "user", ResourceName: "users/2"},
+				},
+			},
+		},
+		{
+			name: "variables of wrapping errors are kept",
+			err: func() error {
+				return Wrap(errors.Join(errors.New("a"), errors.New("b")), "fan out", Var{Name: "order_id", Value: "order-1"})
+			},
+			want: want{
+				code:          codes.Unknown,
+				logLevel:      LogLevelError,
+				detailsHidden: true,
+				runtimeState:  []Var{{Name: "order_id", Value: "order-1"}},
+			},
+		},
+		{
+			name: "unexpected errors take precedence",
+			err: func() error {
+				return Join(NewInvalidArgument("name", "must be set"), errors.New("unexpected"))
+			},
+			want: want{
+				code:          codes.Unknown,
+				logLevel:      LogLevelError,
+				detailsHidden: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			err := tt.err()
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := From(err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.code, got.StatusCode())
+			require.Equal(tt.want.logLevel, got.LogLevel())
+			require.Equal(tt.want.detailsHidden, got.IsDetailsHidden())
+			require.Equal(tt.want.badRequestViolations, got.BadRequestViolations())
+			require.Equal(tt.want.resourceInfos, got.ResourceInfos())
+			require.Equal(tt.want.runtimeState, got.RuntimeState())
+		})
+	}
+}
+
+func TestFrom_AggregatedSingleErrorIsNotShared(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	Init("myservice.example.com")
+	child := NewAborted(ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).HideDetails()
+
+	/* ---------------------------------- When ---------------------------------- */
+	got := From(Wrap(Join(child), "saving order", Var{Name: "order_id", Value: "order-1"}))
+	_ = got.WithContext(context.Background()).RemoveSensitiveDetails().RemoveProvenance()
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal([]Var{{Name: "order_id", Value: "order-1"}}, got.RuntimeState())
+	require.False(got.ErrorInfo().Valid)
+	require.Empty(child.RuntimeState())
+	require.True(child.ErrorInfo().Valid)
+}
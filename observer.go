@@ -0,0 +1,86 @@
+package xerror
+
+import "context"
+
+// Observer is notified about errors. It is used to make errors observable, for example by counting them in metrics.
+//
+// Implementations must be safe for concurrent use, and must not retain the error after the call returns since it may
+// be modified afterwards, for example when its sensitive details are removed.
+type Observer interface {
+	// ObserveError is called with a non-nil error.
+	ObserveError(ctx context.Context, xerr *Error)
+}
+
+// ObserverFunc is an adapter that allows the use of an ordinary function as an Observer.
+type ObserverFunc func(ctx context.Context, xerr *Error)
+
+// ObserveError calls fn(ctx, xerr).
+func (fn ObserverFunc) ObserveError(ctx context.Context, xerr *Error) {
+	fn(ctx, xerr)
+}
+
+// observers holds the registered observers. The zero value means that no observers are registered.
+var observers struct {
+	// responded is notified when an error is returned to a caller by the server integrations, such as the xgrpc
+	// interceptor and the xhttp responders.
+	responded []Observer
+	// constructed is notified when an error is created by one of the exported constructors.
+	constructed []Observer
+}
+
+// ObserverOptions controls when a registered Observer is notified.
+type ObserverOptions struct {
+	// OnConstruction makes the observer also get notified when an error is created by one of the exported
+	// constructors, such as NewInternal(). Note that the same error is then typically observed twice, once when it
+	// is constructed and once when it is returned to the caller.
+	OnConstruction bool
+	// SkipResponded makes the observer NOT get notified when an error is returned to a caller by the server
+	// integrations.
+	SkipResponded bool
+}
+
+// RegisterObserver registers an observer that is notified about errors. By default, it is notified when an error is
+// returned to a caller by the server integrations, such as the xgrpc interceptor and the xhttp responders. See
+// ObserverOptions for how to change this.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func RegisterObserver(o Observer, opts ObserverOptions) {
+	if o == nil {
+		return
+	}
+	if !opts.SkipResponded {
+		observers.responded = append(observers.responded, o)
+	}
+	if opts.OnConstruction {
+		observers.constructed = append(observers.constructed, o)
+	}
+}
+
+// ResetObservers unregisters all observers.
+//
+// It is NOT thread-safe.
+func ResetObservers() {
+	observers.responded = nil
+	observers.constructed = nil
+}
+
+// ObserveResponded notifies the registered observers that the error is being returned to a caller. It is meant to be
+// called by server integrations, and is a no-op if the error is nil.
+func ObserveResponded(ctx context.Context, xerr *Error) {
+	if xerr == nil {
+		return
+	}
+	for _, o := range observers.responded {
+		o.ObserveError(ctx, xerr)
+	}
+}
+
+func observeConstructed(xerr *Error) *Error {
+	if xerr == nil {
+		return nil
+	}
+	for _, o := range observers.constructed {
+		o.ObserveError(context.Background(), xerr)
+	}
+	return xerr
+}
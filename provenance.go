@@ -0,0 +1,124 @@
+package xerror
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tobbstr/xerror/xerrorpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// DefaultMaxHops is the maximum number of hops recorded in the provenance of an error, unless configured otherwise
+// with InitProvenance.
+const DefaultMaxHops = 10
+
+// Provenance records the services that an error has travelled through. See Error.AddHop.
+type Provenance struct {
+	// Hops are the calls that returned the error, starting with the call closest to where the error originated.
+	Hops []Hop
+	// OmittedHops is the number of hops that were left out, since the chain had reached its maximum length. The
+	// omitted hops are the ones furthest from where the error originated.
+	OmittedHops int
+}
+
+// Hop is a call that returned an error.
+type Hop struct {
+	// Domain is the domain of the service that received the error, see Init.
+	Domain string
+	// Method is the method that returned the error, ex. "/order.v1.OrderService/CreateOrder" or "POST /v1/orders".
+	Method string
+	// Code is the status code returned by the method.
+	Code codes.Code
+}
+
+// ProvenanceOptions controls how the provenance of errors is recorded.
+type ProvenanceOptions struct {
+	// MaxHops is the maximum number of hops that are recorded. Once reached, further hops are only counted. If it is
+	// zero, DefaultMaxHops is used.
+	MaxHops int
+}
+
+var maxHops = DefaultMaxHops
+
+// InitProvenance configures how the provenance of errors is recorded.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func InitProvenance(opts ProvenanceOptions) {
+	maxHops = opts.MaxHops
+	if maxHops <= 0 {
+		maxHops = DefaultMaxHops
+	}
+}
+
+// AddHop records that the error was returned by a call to the method, in the provenance detail of the error. The hop
+// consists of the domain of this service, see Init, the method and the current status code of the error. It is meant
+// to be called when converting an error returned by another service, which is done by the client-side conversions in
+// the xgrpc and xhttp packages, if enabled.
+//
+// Since the provenance reveals the internal topology of the system, it is removed along with the other sensitive
+// details, see RemoveSensitiveDetails. It is also removed from the responses of all the server-side conversions, even
+// if the details aren't hidden, except for gRPC servers whose callers are trusted, see RemoveProvenance.
+func (xerr *Error) AddHop(method string) *Error {
+	xerr = xerr.mutable()
+	hop := &xerrorpb.Hop{Domain: maker.domain, Method: method, Code: int32(xerr.StatusCode())}
+
+	existing, err := xerr.findProvenance()
+	if errors.Is(err, errNotFound) {
+		status, err := xerr.status.WithDetails(&xerrorpb.Provenance{Hops: []*xerrorpb.Hop{hop}})
+		if err != nil {
+			panic(fmt.Errorf("%v: %w", err, ErrFailedToAddErrorDetails))
+		}
+		xerr.status = *status
+		return xerr
+	}
+	if len(existing.Hops) < maxHops {
+		existing.Hops = append(existing.Hops, hop)
+	} else {
+		existing.OmittedHops++
+	}
+	xerr.replaceDetail(existing)
+	return xerr
+}
+
+// Provenance returns the services that the error has travelled through, if they have been recorded. See AddHop.
+func (xerr *Error) Provenance() Optional[Provenance] {
+	pb, err := xerr.findProvenance()
+	if errors.Is(err, errNotFound) {
+		return newInvalidOptional[Provenance]()
+	}
+	provenance := Provenance{Hops: make([]Hop, len(pb.Hops)), OmittedHops: int(pb.OmittedHops)}
+	for i, hop := range pb.Hops {
+		provenance.Hops[i] = Hop{Domain: hop.Domain, Method: hop.Method, Code: codes.Code(hop.Code)}
+	}
+	return newValidOptional(provenance)
+}
+
+// RemoveProvenance removes the provenance detail from the error, see AddHop. It is called by the server-side
+// conversions in the xgrpc, xhttp, xconnect, xtwirp, xjsonrpc and xgraphql packages before the error is returned, so
+// that the internal topology of the system isn't revealed to callers. The only exception is gRPC servers that attach
+// the complete error as an envelope, see xgrpc.ServerOptions.AttachEnvelope, since their callers are trusted.
+func (xerr *Error) RemoveProvenance() *Error {
+	xerr = xerr.mutable()
+	pb := xerr.status.Proto()
+	details := make([]*anypb.Any, 0, len(pb.Details))
+	for _, a := range pb.Details {
+		if a.MessageIs(&xerrorpb.Provenance{}) {
+			continue
+		}
+		details = append(details, a)
+	}
+	pb.Details = details
+	xerr.status = *status.FromProto(pb)
+	return xerr
+}
+
+func (xerr *Error) findProvenance() (*xerrorpb.Provenance, error) {
+	for _, detail := range xerr.status.Details() {
+		if v, ok := detail.(*xerrorpb.Provenance); ok {
+			return v, nil
+		}
+	}
+	return nil, errNotFound
+}
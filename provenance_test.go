@@ -0,0 +1,79 @@
+package xerror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestError_AddHop(t *testing.T) {
+	type given struct {
+		maxHops int
+		methods []string
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  Provenance
+	}{
+		{
+			name:  "hops within the limit",
+			given: given{maxHops: 3, methods: []string{"/a.v1.A/Get", "POST /v1/b"}},
+			want: Provenance{Hops: []Hop{
+				{Domain: "myservice.example.com", Method: "/a.v1.A/Get", Code: codes.NotFound},
+				{Domain: "myservice.example.com", Method: "POST /v1/b", Code: codes.NotFound},
+			}},
+		},
+		{
+			name:  "hops beyond the limit are omitted",
+			given: given{maxHops: 1, methods: []string{"/a.v1.A/Get", "POST /v1/b", "GET /v1/c"}},
+			want: Provenance{
+				Hops:        []Hop{{Domain: "myservice.example.com", Method: "/a.v1.A/Get", Code: codes.NotFound}},
+				OmittedHops: 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			Init("myservice.example.com")
+			InitProvenance(ProvenanceOptions{MaxHops: tt.given.maxHops})
+			defer InitProvenance(ProvenanceOptions{})
+			xerr := NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"})
+
+			/* ---------------------------------- When ---------------------------------- */
+			for _, method := range tt.given.methods {
+				_ = xerr.AddHop(method)
+			}
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			got := xerr.Provenance()
+			require.True(got.Valid)
+			require.Equal(tt.want, got.Value)
+			require.Equal([]ResourceInfo{{ResourceType: "user", ResourceName: "users/1"}}, xerr.ResourceInfos())
+
+			_ = xerr.RemoveSensitiveDetails()
+			require.False(xerr.Provenance().Valid)
+		})
+	}
+}
+
+func TestError_RemoveProvenance(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	Init("myservice.example.com")
+	xerr := NewNotFound(ResourceInfo{ResourceType: "user", ResourceName: "users/1"}).
+		AddHop("/a.v1.A/Get").
+		SetDebugInfo("user not in cache", nil)
+
+	/* ---------------------------------- When ---------------------------------- */
+	_ = xerr.RemoveProvenance()
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.False(xerr.Provenance().Valid)
+	require.Equal([]ResourceInfo{{ResourceType: "user", ResourceName: "users/1"}}, xerr.ResourceInfos())
+	require.True(xerr.DebugInfo().Valid)
+	require.Equal(codes.NotFound, xerr.StatusCode())
+}
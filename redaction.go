@@ -0,0 +1,126 @@
+package xerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// RedactedValue is the value that sensitive variables are replaced with by the default redaction policy.
+const RedactedValue = "[REDACTED]"
+
+// Redactor is implemented by values that know how to redact themselves, for example an email address type that only
+// keeps the domain. Variables whose values implement Redactor are rendered using the value returned by Redact.
+type Redactor interface {
+	Redact() any
+}
+
+// RedactionPolicy controls how sensitive variables are rendered.
+type RedactionPolicy struct {
+	// Mask returns the value a sensitive variable is replaced with. If it is nil, the value is replaced with
+	// RedactedValue.
+	Mask func(v Var) any
+	// SensitiveNames is a list of variable names that are always treated as sensitive, even if they weren't marked
+	// as such when added. Ex. "password", "email". The names are compared case-insensitively.
+	SensitiveNames []string
+}
+
+var redaction = struct {
+	mask           func(v Var) any
+	sensitiveNames map[string]struct{}
+}{mask: maskVar}
+
+// InitRedaction configures how sensitive variables are rendered.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func InitRedaction(policy RedactionPolicy) {
+	redaction.mask = policy.Mask
+	if redaction.mask == nil {
+		redaction.mask = maskVar
+	}
+	redaction.sensitiveNames = make(map[string]struct{}, len(policy.SensitiveNames))
+	for _, name := range policy.SensitiveNames {
+		redaction.sensitiveNames[strings.ToLower(name)] = struct{}{}
+	}
+}
+
+func maskVar(Var) any {
+	return RedactedValue
+}
+
+// IsSensitive returns true if the variable was marked as sensitive, or if its name is among the sensitive names of
+// the redaction policy.
+func (v Var) IsSensitive() bool {
+	if v.Sensitive {
+		return true
+	}
+	_, ok := redaction.sensitiveNames[strings.ToLower(v.Name)]
+	return ok
+}
+
+// SafeValue returns the value of the variable that is safe to render, for example in logs. Sensitive values are
+// masked according to the redaction policy, and values implementing Redactor are redacted. Otherwise, the value is
+// returned as-is.
+func (v Var) SafeValue() any {
+	if v.IsSensitive() {
+		return redaction.mask(v)
+	}
+	if r, ok := v.Value.(Redactor); ok {
+		return r.Redact()
+	}
+	return v.Value
+}
+
+// MarshalJSON marshals the variable to JSON using its safe value. See SafeValue.
+func (v Var) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Name  string
+		Value any
+	}{Name: v.Name, Value: v.SafeValue()})
+}
+
+// LogValue implements slog.LogValuer, so that logging a variable with slog renders its safe value. See SafeValue.
+func (v Var) LogValue() slog.Value {
+	return slog.AnyValue(v.SafeValue())
+}
+
+// String returns the variable as "name=value", using its safe value. See SafeValue.
+func (v Var) String() string {
+	return fmt.Sprintf("%s=%v", v.Name, v.SafeValue())
+}
+
+// GoString returns the Go syntax representation of the variable, using its safe value. See SafeValue.
+func (v Var) GoString() string {
+	return fmt.Sprintf("xerror.Var{Name:%q, Value:%#v, Sensitive:%t}", v.Name, v.SafeValue(), v.Sensitive)
+}
+
+// Format implements fmt.Formatter, so that the raw value of a sensitive variable isn't rendered by any of the fmt
+// verbs, even when the variable is part of a slice or a struct. The %v and %s verbs render String, %+v renders the
+// fields and %#v renders GoString. Any other verb is applied to the safe value. See SafeValue.
+func (v Var) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		_, _ = fmt.Fprint(f, v.GoString())
+	case verb == 'v' && f.Flag('+'):
+		_, _ = fmt.Fprintf(f, "{Name:%s Value:%+v Sensitive:%t}", v.Name, v.SafeValue(), v.Sensitive)
+	case verb == 'v' || verb == 's':
+		_, _ = fmt.Fprint(f, v.String())
+	case verb == 'q':
+		_, _ = fmt.Fprintf(f, "%q", v.String())
+	default:
+		_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), v.SafeValue())
+	}
+}
+
+// AddSecretVar adds a sensitive variable to the runtime state. Its value is masked whenever it is rendered, for example
+// by MarshalJSON or the logging and tracing integrations, but the raw value is still accessible through the Value
+// field of the variables returned by RuntimeState.
+func (xerr *Error) AddSecretVar(name string, value any) *Error {
+	xerr = xerr.mutable()
+	if name == "" || value == nil {
+		return xerr
+	}
+	xerr.runtimeState = append(xerr.runtimeState, Var{Name: name, Value: value, Sensitive: true})
+	return xerr
+}
@@ -0,0 +1,146 @@
+package xerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "super-secret-token"
+
+type email string
+
+func (e email) Redact() any {
+	_, domain, _ := strings.Cut(string(e), "@")
+	return "***@" + domain
+}
+
+func TestRedaction(t *testing.T) {
+	type given struct {
+		policy RedactionPolicy
+		xerr   func() *Error
+	}
+	type want struct {
+		safeValues []any
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name: "secret var",
+			given: given{
+				xerr: func() *Error {
+					return NewInternal(errors.New("boom")).AddSecretVar("token", secret)
+				},
+			},
+			want: want{safeValues: []any{RedactedValue}},
+		},
+		{
+			name: "sensitive var added with AddVars",
+			given: given{
+				xerr: func() *Error {
+					return NewInternal(errors.New("boom")).AddVars(Var{Name: "token", Value: secret, Sensitive: true})
+				},
+			},
+			want: want{safeValues: []any{RedactedValue}},
+		},
+		{
+			name: "sensitive name in policy",
+			given: given{
+				policy: RedactionPolicy{SensitiveNames: []string{"TOKEN"}},
+				xerr: func() *Error {
+					return NewInternal(errors.New("boom")).AddVar("token", secret).AddVar("user_id", 123)
+				},
+			},
+			want: want{safeValues: []any{RedactedValue, 123}},
+		},
+		{
+			name: "custom mask",
+			given: given{
+				policy: RedactionPolicy{Mask: func(v Var) any { return "<" + v.Name + ">" }},
+				xerr: func() *Error {
+					return NewInternal(errors.New("boom")).AddSecretVar("token", secret)
+				},
+			},
+			want: want{safeValues: []any{"<token>"}},
+		},
+		{
+			name: "redactor value",
+			given: given{
+				xerr: func() *Error {
+					return NewInternal(errors.New("boom")).AddVar("email", email("john.doe."+secret+"@example.com"))
+				},
+			},
+			want: want{safeValues: []any{"***@example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			InitRedaction(tt.given.policy)
+			defer InitRedaction(RedactionPolicy{})
+			xerr := tt.given.xerr()
+
+			/* ---------------------------------- When ---------------------------------- */
+			marshalled, err := json.Marshal(xerr)
+
+			var logged bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logged, nil))
+			attrs := make([]any, 0, len(xerr.RuntimeState()))
+			for _, v := range xerr.RuntimeState() {
+				attrs = append(attrs, slog.Any(v.Name, v))
+			}
+			logger.Error(xerr.StatusMessage(), attrs...)
+
+			var loggedText bytes.Buffer
+			slog.New(slog.NewTextHandler(&loggedText, nil)).Error(xerr.StatusMessage(), slog.Any("vars", xerr.RuntimeState()))
+
+			formatted := []string{
+				fmt.Sprintf("%v", xerr.RuntimeState()),
+				fmt.Sprintf("%+v", xerr.RuntimeState()),
+				fmt.Sprintf("%#v", xerr.RuntimeState()),
+				fmt.Sprintf("%s", xerr.RuntimeState()),
+				fmt.Sprint(xerr.RuntimeState()),
+				fmt.Sprintf("%v", struct{ Vars []Var }{Vars: xerr.RuntimeState()}),
+			}
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.NoError(err)
+			require.NotContains(string(marshalled), secret)
+			require.NotContains(logged.String(), secret)
+			require.NotContains(loggedText.String(), secret)
+			for _, f := range formatted {
+				require.NotContains(f, secret)
+			}
+
+			safeValues := make([]any, 0, len(xerr.RuntimeState()))
+			for _, v := range xerr.RuntimeState() {
+				safeValues = append(safeValues, v.SafeValue())
+			}
+			require.Equal(tt.want.safeValues, safeValues)
+		})
+	}
+}
+
+func TestVar_Format(t *testing.T) {
+	v := Var{Name: "user_id", Value: 123}
+	secretVar := Var{Name: "token", Value: secret, Sensitive: true}
+
+	require := require.New(t)
+	require.Equal("user_id=123", fmt.Sprintf("%v", v))
+	require.Equal("user_id=123", v.String())
+	require.Equal("{Name:user_id Value:123 Sensitive:false}", fmt.Sprintf("%+v", v))
+	require.Equal(`xerror.Var{Name:"user_id", Value:123, Sensitive:false}`, fmt.Sprintf("%#v", v))
+	require.Equal(`"user_id=123"`, fmt.Sprintf("%q", v))
+	require.Equal("[user_id=123 token=[REDACTED]]", fmt.Sprintf("%v", []Var{v, secretVar}))
+	require.Equal(`xerror.Var{Name:"token", Value:"[REDACTED]", Sensitive:true}`, fmt.Sprintf("%#v", secretVar))
+}
@@ -0,0 +1,123 @@
+package xerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// TraceContext identifies the trace and the span that were active when the error was encountered. It is used to jump
+// from a logged error, or a failing API response, to the trace.
+type TraceContext struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+// TraceExposure controls whether the trace context is exposed to callers, and in that case, in which error detail.
+type TraceExposure uint8
+
+const (
+	// TraceExposureNone means that the trace context is not exposed to callers. It is still logged.
+	TraceExposureNone TraceExposure = iota
+	// TraceExposureRequestInfo means that the trace context is exposed in a RequestInfo detail, where the request id
+	// is the trace id and the serving data contains the span id.
+	TraceExposureRequestInfo
+	// TraceExposureDebugInfo means that the trace context is exposed in the DebugInfo detail.
+	TraceExposureDebugInfo
+)
+
+// TraceContextOptions controls how trace contexts are attached to errors by the WithContext method.
+type TraceContextOptions struct {
+	// Extract returns the trace context of the active span in ctx, and false if there is none. See the xotel package
+	// for an OpenTelemetry implementation.
+	Extract func(ctx context.Context) (TraceContext, bool)
+	// Exposure controls whether the trace context is exposed to callers. In either case, the trace context is never
+	// exposed when the error details are hidden.
+	Exposure TraceExposure
+}
+
+var traceContextOpts TraceContextOptions
+
+// InitTraceContext configures how trace contexts are attached to errors. Unless it is called, the WithContext method
+// is a no-op.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+//
+// Ex.
+//
+//	xerror.InitTraceContext(xerror.TraceContextOptions{
+//		Extract:  xotel.ExtractTraceContext,
+//		Exposure: xerror.TraceExposureRequestInfo,
+//	})
+func InitTraceContext(opts TraceContextOptions) {
+	traceContextOpts = opts
+}
+
+// WithContext enriches the error with the values carried by ctx:
+//   - The variables added with WithVars are merged into the runtime state. Variables already in the runtime state
+//     take precedence over context variables with the same name.
+//   - The trace context of the active span is attached, if there is one and if the error doesn't already have a trace
+//     context. The trace context is included when the error is logged and in the MarshalJSON output. Depending on the
+//     options given to InitTraceContext, it is also exposed to callers, but only if the error details aren't hidden.
+//
+// The xgrpc interceptor and the xhttp responders call this method before responding, so typically it only needs to be
+// called explicitly when the error is logged elsewhere. Calling it more than once is safe.
+func (xerr *Error) WithContext(ctx context.Context) *Error {
+	if xerr == nil || ctx == nil {
+		return xerr
+	}
+	xerr = xerr.mutable()
+	xerr.mergeVars(ctx)
+	if traceContextOpts.Extract == nil || xerr.traceContext.Valid {
+		return xerr
+	}
+	tc, ok := traceContextOpts.Extract(ctx)
+	if !ok || tc.TraceID == "" {
+		return xerr
+	}
+	xerr.traceContext = newValidOptional(tc)
+
+	if xerr.detailsHidden {
+		return xerr
+	}
+	switch traceContextOpts.Exposure {
+	case TraceExposureRequestInfo:
+		detail := errdetails.RequestInfo{RequestId: tc.TraceID, ServingData: "span_id=" + tc.SpanID}
+		status, err := xerr.status.WithDetails(&detail)
+		if err != nil {
+			panic(fmt.Errorf("%v: %w", err, ErrFailedToAddErrorDetails))
+		}
+		xerr.status = *status
+	case TraceExposureDebugInfo:
+		traceDetail := "trace_id=" + tc.TraceID + " span_id=" + tc.SpanID
+		existing, err := xerr.findDebugInfo()
+		if errors.Is(err, errNotFound) {
+			_ = xerr.SetDebugInfo(traceDetail, nil)
+			break
+		}
+		if existing.Detail == "" {
+			existing.Detail = traceDetail
+		} else {
+			existing.Detail += " (" + traceDetail + ")"
+		}
+		xerr.replaceDetail(existing)
+	}
+	return xerr
+}
+
+// TraceContext returns the trace context attached by the WithContext method. If there is none, it returns an invalid
+// optional.
+func (xerr *Error) TraceContext() Optional[TraceContext] {
+	return xerr.traceContext
+}
+
+// isTraceRequestInfo returns true if the detail is the RequestInfo detail added by the WithContext method.
+func (xerr *Error) isTraceRequestInfo(detail any) bool {
+	info, ok := detail.(*errdetails.RequestInfo)
+	if !ok || !xerr.traceContext.Valid {
+		return false
+	}
+	return info.RequestId == xerr.traceContext.Value.TraceID
+}
@@ -0,0 +1,207 @@
+package xerror
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Violations collects validation failures, so that a handler can check many fields and return all problems at once.
+// The zero value is ready to use.
+//
+// Err returns nil if nothing was collected. Otherwise it returns an InvalidArgument error, an OutOfRange error if all
+// collected field violations are range violations, or a FailedPrecondition error if only precondition violations were
+// collected. Since a client must fix its request before preconditions are worth checking, precondition violations are
+// left out of the error when there are field violations.
+//
+// Ex.
+//
+//	var v xerror.Violations
+//	v.AddIf(req.FullName == "", "fullName", "must be set")
+//	for i, addr := range req.EmailAddresses {
+//		validateEmailAddress(v.Nested(xerror.Field("emailAddresses").Index(i).JSON()), addr)
+//	}
+//	if err := v.Err(); err != nil {
+//		return err
+//	}
+type Violations struct {
+	// prefix is the field path that the fields of added violations are relative to.
+	prefix string
+	// list is shared between a collector and the collectors returned by Nested.
+	list *violationList
+}
+
+type violationList struct {
+	fields        []fieldViolation
+	preconditions []PreconditionViolation
+}
+
+type fieldViolation struct {
+	BadRequestViolation
+	outOfRange bool
+}
+
+// Add adds a field violation. The field is relative to the prefix of the collector, see Nested. See NewInvalidArgument
+// for the format of the field and the description.
+func (v *Violations) Add(field, description string) *Violations {
+	v.addField(field, description, false)
+	return v
+}
+
+// AddIf adds a field violation if cond is true. See Add.
+func (v *Violations) AddIf(cond bool, field, description string) *Violations {
+	if cond {
+		v.addField(field, description, false)
+	}
+	return v
+}
+
+// AddOutOfRange adds a field violation for a value that is outside of its valid range. See Add.
+func (v *Violations) AddOutOfRange(field, description string) *Violations {
+	v.addField(field, description, true)
+	return v
+}
+
+// AddPrecondition adds a precondition violation. Unlike fields, subjects aren't affected by the prefix of the
+// collector. See NewPreconditionFailure for the meaning of the parameters.
+func (v *Violations) AddPrecondition(subject, typ, description string) *Violations {
+	v.init()
+	v.list.preconditions = append(v.list.preconditions, PreconditionViolation{
+		Subject:     subject,
+		Typ:         typ,
+		Description: description,
+	})
+	return v
+}
+
+// Nested returns a collector whose fields are relative to the prefix, ex. Nested("address").Add("street", ...) adds a
+// violation for the field "address.street". Violations added to the returned collector are also collected by v, so
+// it can be handed to the validator of a nested message.
+func (v *Violations) Nested(prefix string) *Violations {
+	v.init()
+	return &Violations{prefix: v.path(prefix), list: v.list}
+}
+
+// Merge adds all violations collected by other, with their fields made relative to the prefix of v. If other shares
+// its violations with v, ex. since it was returned by v.Nested, then they are already collected by v and nothing is
+// added.
+func (v *Violations) Merge(other *Violations) *Violations {
+	if other == nil || other.list == nil || other.list == v.list {
+		return v
+	}
+	for _, fv := range other.list.fields {
+		v.addField(fv.Field, fv.Description, fv.outOfRange)
+	}
+	if len(other.list.preconditions) > 0 {
+		v.init()
+		v.list.preconditions = append(v.list.preconditions, other.list.preconditions...)
+	}
+	return v
+}
+
+// MergeError adds the violations of an InvalidArgument, OutOfRange or FailedPrecondition error, typically returned by
+// the validator of a nested message, with their fields made relative to the prefix of v. It returns nil if err is nil
+// or was merged. Any other error can't be expressed as violations, so it is returned as-is for the caller to handle.
+//
+// Ex.
+//
+//	if err := v.Nested("address").MergeError(req.Address.Validate()); err != nil {
+//		return err
+//	}
+func (v *Violations) MergeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var xerr *Error
+	if !errors.As(err, &xerr) {
+		return err
+	}
+	switch xerr.StatusCode() {
+	case codes.InvalidArgument, codes.OutOfRange:
+		outOfRange := xerr.StatusCode() == codes.OutOfRange
+		for _, bv := range xerr.BadRequestViolations() {
+			v.addField(bv.Field, bv.Description, outOfRange)
+		}
+	case codes.FailedPrecondition:
+		v.init()
+		v.list.preconditions = append(v.list.preconditions, xerr.PreconditionViolations()...)
+	default:
+		return err
+	}
+	return nil
+}
+
+// Len returns the number of collected violations.
+func (v *Violations) Len() int {
+	if v.list == nil {
+		return 0
+	}
+	return len(v.list.fields) + len(v.list.preconditions)
+}
+
+// Err returns an error with the collected violations, or nil if there are none. See Violations for which error is
+// returned. The error is an *Error, which is returned as an error so that returning the result directly from a function
+// that returns error yields a nil error when there are no violations.
+func (v *Violations) Err() error {
+	if v.Len() == 0 {
+		return nil
+	}
+	return v.xerr()
+}
+
+// xerr returns the error with the collected violations. There must be at least one violation.
+func (v *Violations) xerr() *Error {
+	if len(v.list.fields) == 0 {
+		if len(v.list.preconditions) == 1 {
+			p := v.list.preconditions[0]
+			return NewPreconditionFailure(p.Subject, p.Typ, p.Description)
+		}
+		return NewPreconditionFailureBatch(v.list.preconditions)
+	}
+
+	outOfRange := true
+	violations := make([]BadRequestViolation, len(v.list.fields))
+	for i, fv := range v.list.fields {
+		outOfRange = outOfRange && fv.outOfRange
+		violations[i] = fv.BadRequestViolation
+	}
+	switch {
+	case outOfRange && len(violations) == 1:
+		return NewOutOfRange(violations[0].Field, violations[0].Description)
+	case outOfRange:
+		return NewOutOfRangeBatch(violations)
+	case len(violations) == 1:
+		return NewInvalidArgument(violations[0].Field, violations[0].Description)
+	default:
+		return NewInvalidArgumentBatch(violations)
+	}
+}
+
+func (v *Violations) init() {
+	if v.list == nil {
+		v.list = &violationList{}
+	}
+}
+
+func (v *Violations) addField(field, description string, outOfRange bool) {
+	v.init()
+	v.list.fields = append(v.list.fields, fieldViolation{
+		BadRequestViolation: BadRequestViolation{Field: v.path(field), Description: description},
+		outOfRange:          outOfRange,
+	})
+}
+
+// path returns the field relative to the prefix of the collector. Fields that start with a subscript, ex. "[2]", are
+// appended to the prefix without a separator.
+func (v *Violations) path(field string) string {
+	switch {
+	case v.prefix == "":
+		return field
+	case field == "":
+		return v.prefix
+	case field[0] == '[':
+		return v.prefix + field
+	default:
+		return v.prefix + "." + field
+	}
+}
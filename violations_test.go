@@ -0,0 +1,191 @@
+package xerror
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestViolations_Err(t *testing.T) {
+	type want struct {
+		isNil                  bool
+		code                   codes.Code
+		badRequestViolations   []BadRequestViolation
+		preconditionViolations []PreconditionViolation
+	}
+	tests := []struct {
+		name    string
+		collect func(v *Violations)
+		want    want
+	}{
+		{
+			name:    "no violations",
+			collect: func(v *Violations) {},
+			want:    want{isNil: true},
+		},
+		{
+			name: "false conditions are ignored",
+			collect: func(v *Violations) {
+				v.AddIf(false, "fullName", "must be set")
+			},
+			want: want{isNil: true},
+		},
+		{
+			name: "single field violation",
+			collect: func(v *Violations) {
+				v.AddIf(true, "fullName", "must be set")
+			},
+			want: want{
+				code:                 codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{{Field: "fullName", Description: "must be set"}},
+			},
+		},
+		{
+			name: "only range violations",
+			collect: func(v *Violations) {
+				v.AddOutOfRange("age", "must be greater than 0").AddOutOfRange("height", "must be less than 300")
+			},
+			want: want{
+				code: codes.OutOfRange,
+				badRequestViolations: []BadRequestViolation{
+					{Field: "age", Description: "must be greater than 0"},
+					{Field: "height", Description: "must be less than 300"},
+				},
+			},
+		},
+		{
+			name: "mixed field violations",
+			collect: func(v *Violations) {
+				v.Add("fullName", "must be set").AddOutOfRange("age", "must be greater than 0")
+			},
+			want: want{
+				code: codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{
+					{Field: "fullName", Description: "must be set"},
+					{Field: "age", Description: "must be greater than 0"},
+				},
+			},
+		},
+		{
+			name: "only precondition violations",
+			collect: func(v *Violations) {
+				v.AddPrecondition("google.com/cloud", "TOS", "Terms of service not accepted")
+			},
+			want: want{
+				code: codes.FailedPrecondition,
+				preconditionViolations: []PreconditionViolation{
+					{Subject: "google.com/cloud", Typ: "TOS", Description: "Terms of service not accepted"},
+				},
+			},
+		},
+		{
+			name: "field violations take precedence over precondition violations",
+			collect: func(v *Violations) {
+				v.AddPrecondition("google.com/cloud", "TOS", "Terms of service not accepted")
+				v.Add("fullName", "must be set")
+			},
+			want: want{
+				code:                 codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{{Field: "fullName", Description: "must be set"}},
+			},
+		},
+		{
+			name: "nested violations",
+			collect: func(v *Violations) {
+				v.Add("fullName", "must be set")
+				emails := v.Nested("emailAddresses")
+				emails.Nested("[1]").Add("email", "must be a valid email address")
+				emails.Nested("[3]").Add("type[2]", "must be HOME or WORK").Add("", "must not be a duplicate")
+			},
+			want: want{
+				code: codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{
+					{Field: "fullName", Description: "must be set"},
+					{Field: "emailAddresses[1].email", Description: "must be a valid email address"},
+					{Field: "emailAddresses[3].type[2]", Description: "must be HOME or WORK"},
+					{Field: "emailAddresses[3]", Description: "must not be a duplicate"},
+				},
+			},
+		},
+		{
+			name: "merged violations",
+			collect: func(v *Violations) {
+				var address Violations
+				address.Add("street", "must be set")
+				v.Nested("address").Merge(&address)
+			},
+			want: want{
+				code:                 codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{{Field: "address.street", Description: "must be set"}},
+			},
+		},
+		{
+			name: "merged nested collector",
+			collect: func(v *Violations) {
+				address := v.Nested("address")
+				address.Add("street", "must be set")
+				v.Merge(address)
+				address.Merge(v)
+			},
+			want: want{
+				code:                 codes.InvalidArgument,
+				badRequestViolations: []BadRequestViolation{{Field: "address.street", Description: "must be set"}},
+			},
+		},
+		{
+			name: "merged errors",
+			collect: func(v *Violations) {
+				address := v.Nested("address")
+				_ = address.MergeError(nil)
+				_ = address.MergeError(NewOutOfRange("number", "must be greater than 0"))
+				_ = address.MergeError(NewOutOfRangeBatch([]BadRequestViolation{{Field: "zip", Description: "too long"}}))
+			},
+			want: want{
+				code: codes.OutOfRange,
+				badRequestViolations: []BadRequestViolation{
+					{Field: "address.number", Description: "must be greater than 0"},
+					{Field: "address.zip", Description: "too long"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			var v Violations
+			tt.collect(&v)
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := v.Err()
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			if tt.want.isNil {
+				// Compared to nil as an interface, since a nil *Error returned as an error isn't a nil error
+				require.True(got == nil)
+				return
+			}
+			var xerr *Error
+			require.ErrorAs(got, &xerr)
+			require.Equal(tt.want.code, xerr.StatusCode())
+			require.Equal(tt.want.badRequestViolations, xerr.BadRequestViolations())
+			require.Equal(tt.want.preconditionViolations, xerr.PreconditionViolations())
+		})
+	}
+}
+
+func TestViolations_MergeError(t *testing.T) {
+	var v Violations
+
+	require.NoError(t, v.MergeError(NewInvalidArgument("name", "must be set")))
+
+	unmergeable := errors.New("database unavailable")
+	require.Same(t, unmergeable, v.MergeError(unmergeable))
+
+	internal := NewInternal(unmergeable)
+	require.Same(t, internal, v.MergeError(internal))
+
+	require.Equal(t, 1, v.Len())
+}
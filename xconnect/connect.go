@@ -0,0 +1,123 @@
+/*
+Package xconnect integrates xerrors with Connect, see connectrpc.com/connect.
+*/
+package xconnect
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/tobbstr/xerror"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// typeURLPrefix is the prefix of the type URLs of status details, see anypb.Any.
+const typeURLPrefix = "type.googleapis.com/"
+
+// NewInterceptor returns a Connect interceptor that converts the xerrors returned by handlers to Connect errors, see
+// ConnectErrorFrom. It's the Connect counterpart of xgrpc.UnaryXErrorInterceptor, so before the conversion the error is
+// enriched with the values carried by the context (see xerror.Error.WithContext), and it is passed to the observers
+// registered with xerror.RegisterObserver. Sensitive details are removed from errors that are marked as hidden.
+//
+// The interceptor only applies to handlers, both unary and streaming. Clients convert the returned errors using
+// ErrorFrom.
+//
+// Ex.
+//
+//	path, handler := orderv1connect.NewOrderServiceHandler(server, connect.WithInterceptors(xconnect.NewInterceptor()))
+func NewInterceptor() connect.Interceptor {
+	return interceptor{}
+}
+
+type interceptor struct{}
+
+func (interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		ctx = xerror.WithVarScope(ctx)
+		resp, err := next(ctx, req)
+		if err != nil {
+			return resp, handlerError(ctx, err)
+		}
+		return resp, nil
+	}
+}
+
+func (interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx = xerror.WithVarScope(ctx)
+		if err := next(ctx, conn); err != nil {
+			return handlerError(ctx, err)
+		}
+		return nil
+	}
+}
+
+func handlerError(ctx context.Context, err error) error {
+	var xerr *xerror.Error
+	if !errors.As(err, &xerr) {
+		return err
+	}
+	// Aggregated errors, see xerror.Join, are converted to a single xerror
+	xerr = xerror.From(err)
+	_ = xerr.WithContext(ctx)
+	xerror.ObserveResponded(ctx, xerr)
+	if xerr.IsDetailsHidden() {
+		_ = xerr.RemoveSensitiveDetails()
+	}
+	_ = xerr.RemoveProvenance()
+	return ConnectErrorFrom(xerr)
+}
+
+// ConnectErrorFrom converts the xerror to a Connect error, with the same code, message and details. It doesn't remove
+// any details, so if the error is returned to a caller, the sensitive details of hidden errors should be removed first,
+// see xerror.Error.RemoveSensitiveDetails. NewInterceptor takes care of that.
+func ConnectErrorFrom(xerr *xerror.Error) *connect.Error {
+	st := xerr.StatusProto()
+	connectErr := connect.NewError(connect.Code(st.GetCode()), errors.New(st.GetMessage()))
+	for _, detail := range st.GetDetails() {
+		// Details are passed as Any messages, which is infallible
+		d, _ := connect.NewErrorDetail(detail)
+		connectErr.AddDetail(d)
+	}
+	return connectErr
+}
+
+// ErrorFrom is a convenience function that creates a new xerror from an error returned by a Connect client, keeping
+// its code, message and details. If the error isn't a Connect error, then it returns an xerror with the status code
+// Unknown. If the error already is an xerror, then it is returned as is.
+//
+// Ex.
+//
+//	resp, err := client.CreateOrder(ctx, connect.NewRequest(req))
+//	if err != nil {
+//	  return xconnect.ErrorFrom(err).AddVar("customer_id", req.CustomerId)
+//	}
+func ErrorFrom(err error) *xerror.Error {
+	if err == nil {
+		return nil
+	}
+	var xerr *xerror.Error
+	if errors.As(err, &xerr) {
+		return xerr
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return new(xerror.Error).SetStatus(status.New(codes.Unknown, err.Error()))
+	}
+	st := &spb.Status{Code: int32(connectErr.Code()), Message: connectErr.Message()}
+	for _, detail := range connectErr.Details() {
+		st.Details = append(st.Details, &anypb.Any{TypeUrl: typeURLPrefix + detail.Type(), Value: detail.Bytes()})
+	}
+	return new(xerror.Error).SetStatus(status.FromProto(st))
+}
@@ -0,0 +1,93 @@
+package xconnect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestInterceptor(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type want struct {
+		code       codes.Code
+		message    string
+		violations []xerror.BadRequestViolation
+		debugInfo  bool
+	}
+	tests := []struct {
+		name string
+		err  error
+		want want
+	}{
+		{
+			name: "xerror with details",
+			err:  xerror.NewInvalidArgument("age", "must be greater than 0"),
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+			},
+		},
+		{
+			name: "xerror with shown debug info",
+			err:  xerror.NewInternal(errors.New("boom")).SetDebugInfo("stack", nil).ShowDetails(),
+			want: want{code: codes.Internal, message: "boom", debugInfo: true},
+		},
+		{
+			name: "xerror with hidden debug info",
+			err:  xerror.NewInternal(errors.New("boom")).SetDebugInfo("stack", nil),
+			want: want{code: codes.Internal, message: "boom"},
+		},
+		{
+			name: "connect error",
+			err:  connect.NewError(connect.CodeAborted, errors.New("conflict")),
+			want: want{code: codes.Aborted, message: "conflict"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			const procedure = "/test.v1.TestService/Do"
+			handler := connect.NewUnaryHandler(
+				procedure,
+				func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+					return nil, tt.err
+				},
+				connect.WithInterceptors(NewInterceptor()),
+			)
+			server := httptest.NewServer(handler)
+			defer server.Close()
+			client := connect.NewClient[emptypb.Empty, emptypb.Empty](http.DefaultClient, server.URL+procedure)
+
+			/* ---------------------------------- When ---------------------------------- */
+			_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+			got := ErrorFrom(err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.code, got.StatusCode())
+			require.Equal(tt.want.message, got.StatusMessage())
+			require.Equal(tt.want.violations, got.BadRequestViolations())
+			require.Equal(tt.want.debugInfo, got.DebugInfo().Valid)
+		})
+	}
+}
+
+func TestErrorFrom(t *testing.T) {
+	xerr := xerror.NewCancelled()
+	require.Same(t, xerr, ErrorFrom(xerr))
+	require.Nil(t, ErrorFrom(nil))
+
+	got := ErrorFrom(errors.New("some error"))
+	require.Equal(t, codes.Unknown, got.StatusCode())
+	require.Equal(t, "some error", got.StatusMessage())
+}
@@ -5,16 +5,22 @@ logged at a later time. It also provides a way to categorize errors into differe
 package xerror
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
+	"time"
 
+	"github.com/tobbstr/xerror/xerrorpb"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 /*
@@ -46,9 +52,14 @@ func newInvalidOptional[T any]() Optional[T] {
 
 // Var models what the circumstances were when the error was encountered and is used to provide additional context
 // to the error. Its purpose is to be logged and thereby give context to the error in the logs.
+//
+// Values that must not end up verbatim in logs, such as email addresses or tokens, should be marked as sensitive. See
+// the AddSecretVar method and the Redactor interface.
 type Var struct {
 	Name  string
 	Value any
+	// Sensitive marks the value as sensitive, which means that it is masked whenever it's rendered. See SafeValue.
+	Sensitive bool
 }
 
 // LogLevel is used to control the way the error is logged. For example as an error, warning, notice etc.
@@ -62,6 +73,22 @@ const (
 	LogLevelError
 )
 
+// String returns the lower case name of the log level, ex. "warn".
+func (lvl LogLevel) String() string {
+	switch lvl {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	default:
+		return "unspecified"
+	}
+}
+
 type Error struct {
 	logLevel      LogLevel
 	status        status.Status
@@ -69,6 +96,10 @@ type Error struct {
 	// runtimeState is a snapshot of the state of the application when the error was encountered. It is used to provide
 	// additional context to the error and is used to log the circumstances when the error was encountered.
 	runtimeState []Var
+	// traceContext identifies the trace and span that were active when the error was encountered.
+	traceContext Optional[TraceContext]
+	// sentinel is true for errors created by NewSentinel. Since they are shared, they are never modified, see mutable.
+	sentinel bool
 }
 
 func (xerr *Error) Error() string {
@@ -99,6 +130,18 @@ func (xerr *Error) findDebugInfo() (*errdetails.DebugInfo, error) {
 	return nil, errNotFound
 }
 
+func (xerr *Error) findRetryInfo() (*errdetails.RetryInfo, error) {
+	for _, detail := range xerr.status.Details() {
+		switch v := detail.(type) {
+		case *errdetails.RetryInfo:
+			return v, nil
+		default:
+			continue
+		}
+	}
+	return nil, errNotFound
+}
+
 func (xerr *Error) findPreconditionFailure() (*errdetails.PreconditionFailure, error) {
 	for _, detail := range xerr.status.Details() {
 		switch v := detail.(type) {
@@ -151,6 +194,44 @@ func (xerr *Error) findResourceInfos() ([]*errdetails.ResourceInfo, error) {
 	return infos, nil
 }
 
+// mutable returns the error itself, or a copy of it if it is a sentinel, see NewSentinel. It must be called by every
+// method that modifies the error, which must then modify and return the returned error.
+func (xerr *Error) mutable() *Error {
+	if !xerr.sentinel {
+		return xerr
+	}
+	return xerr.clone()
+}
+
+// clone returns a deep copy of the error, which isn't a sentinel.
+func (xerr *Error) clone() *Error {
+	return &Error{
+		logLevel:      xerr.logLevel,
+		status:        *status.FromProto(xerr.status.Proto()),
+		detailsHidden: xerr.detailsHidden,
+		runtimeState:  slices.Clone(xerr.runtimeState),
+		traceContext:  xerr.traceContext,
+	}
+}
+
+// replaceDetail replaces the first detail of the same type as the given one. It must be called after modifying a
+// detail returned by one of the find methods, since those are copies of the details in the status.
+func (xerr *Error) replaceDetail(detail proto.Message) {
+	pb := xerr.status.Proto()
+	for i, a := range pb.Details {
+		if !a.MessageIs(detail) {
+			continue
+		}
+		replacement, err := anypb.New(detail)
+		if err != nil {
+			panic(fmt.Errorf("%v: %w", err, ErrFailedToAddErrorDetails))
+		}
+		pb.Details[i] = replacement
+		xerr.status = *status.FromProto(pb)
+		return
+	}
+}
+
 // AddBadRequestViolations adds a list of bad request violations to the error details. If the error details already
 // contain bad request violations, the new ones are appended to the existing ones.
 //
@@ -160,6 +241,7 @@ func (xerr *Error) findResourceInfos() ([]*errdetails.ResourceInfo, error) {
 //
 // See: https://cloud.google.com/apis/design/errors#error_payloads
 func (xerr *Error) AddBadRequestViolations(violations []BadRequestViolation) *Error {
+	xerr = xerr.mutable()
 	violationspb := make([]*errdetails.BadRequest_FieldViolation, len(violations))
 	for i, v := range violations {
 		violationspb[i] = &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description}
@@ -175,12 +257,14 @@ func (xerr *Error) AddBadRequestViolations(violations []BadRequestViolation) *Er
 		return xerr
 	}
 	existing.FieldViolations = append(existing.FieldViolations, violationspb...)
+	xerr.replaceDetail(existing)
 	return xerr
 }
 
 // AddPreconditionViolations adds a list of precondition violations to the error details. If the error details already
 // contain precondition violations, the new ones are appended to the existing ones.
 func (xerr *Error) AddPreconditionViolations(violations []PreconditionViolation) *Error {
+	xerr = xerr.mutable()
 	violationspb := make([]*errdetails.PreconditionFailure_Violation, len(violations))
 	for i, v := range violations {
 		violationspb[i] = &errdetails.PreconditionFailure_Violation{Description: v.Description, Subject: v.Subject, Type: v.Typ}
@@ -196,6 +280,7 @@ func (xerr *Error) AddPreconditionViolations(violations []PreconditionViolation)
 		return xerr
 	}
 	existing.Violations = append(existing.Violations, violationspb...)
+	xerr.replaceDetail(existing)
 	return xerr
 }
 
@@ -210,6 +295,7 @@ func (xerr *Error) AddPreconditionViolations(violations []PreconditionViolation)
 //
 // See: https://cloud.google.com/apis/design/errors#error_payloads
 func (xerr *Error) SetErrorInfo(domain, reason string, metadata map[string]any) *Error {
+	xerr = xerr.mutable()
 	if reason == "" {
 		return xerr
 	}
@@ -233,6 +319,7 @@ func (xerr *Error) SetErrorInfo(domain, reason string, metadata map[string]any)
 	existing.Domain = domain
 	existing.Reason = reason
 	existing.Metadata = metadatapb
+	xerr.replaceDetail(existing)
 	return xerr
 }
 
@@ -245,6 +332,7 @@ func (xerr *Error) SetErrorInfo(domain, reason string, metadata map[string]any)
 //
 // See: https://cloud.google.com/apis/design/errors#error_payloads
 func (xerr *Error) AddResourceInfos(infos []ResourceInfo) *Error {
+	xerr = xerr.mutable()
 	for _, info := range infos {
 		detail := errdetails.ResourceInfo{
 			Description:  info.Description,
@@ -268,6 +356,7 @@ func (xerr *Error) AddResourceInfos(infos []ResourceInfo) *Error {
 // debug info, use the runtime state instead (the AddVar() and AddVars() methods). It's is however possible to include
 // a debug info detail and still not return it to the caller by calling the HideDetails() method.
 func (xerr *Error) SetDebugInfo(detail string, stackEntries []string) *Error {
+	xerr = xerr.mutable()
 	if detail == "" {
 		return xerr
 	}
@@ -285,6 +374,31 @@ func (xerr *Error) SetDebugInfo(detail string, stackEntries []string) *Error {
 
 	existing.Detail = detail
 	existing.StackEntries = stackEntries
+	xerr.replaceDetail(existing)
+	return xerr
+}
+
+// SetRetryInfo sets the retry info detail, telling the caller how long to wait before retrying the call. If the error
+// details already contain a retry info detail, it is overwritten. If the delay isn't positive, the operation is a no-op.
+func (xerr *Error) SetRetryInfo(retryDelay time.Duration) *Error {
+	xerr = xerr.mutable()
+	if retryDelay <= 0 {
+		return xerr
+	}
+
+	existing, err := xerr.findRetryInfo()
+	if errors.Is(err, errNotFound) {
+		detail := errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)}
+		status, err := xerr.status.WithDetails(&detail)
+		if err != nil {
+			panic(fmt.Errorf("%v: %w", err, ErrFailedToAddErrorDetails))
+		}
+		xerr.status = *status
+		return xerr
+	}
+
+	existing.RetryDelay = durationpb.New(retryDelay)
+	xerr.replaceDetail(existing)
 	return xerr
 }
 
@@ -308,6 +422,7 @@ type QuotaViolation struct {
 // AddQuotaViolations adds a list of quota violations to the error details. If the error details already contain quota
 // violations, the new ones are appended to the existing ones.
 func (xerr *Error) AddQuotaViolations(violations []QuotaViolation) *Error {
+	xerr = xerr.mutable()
 	violationspb := make([]*errdetails.QuotaFailure_Violation, len(violations))
 	for i, v := range violations {
 		violationspb[i] = &errdetails.QuotaFailure_Violation{Subject: v.Subject, Description: v.Description}
@@ -323,6 +438,7 @@ func (xerr *Error) AddQuotaViolations(violations []QuotaViolation) *Error {
 		return xerr
 	}
 	existing.Violations = append(existing.Violations, violationspb...)
+	xerr.replaceDetail(existing)
 	return xerr
 }
 
@@ -430,6 +546,22 @@ func (xerr *Error) DebugInfo() Optional[DebugInfo] {
 	return newValidOptional(DebugInfo{Detail: pb.Detail, StackEntries: pb.StackEntries})
 }
 
+// RetryInfo describes when a call that failed can be retried.
+type RetryInfo struct {
+	// RetryDelay is the minimum amount of time the caller should wait before retrying the call.
+	RetryDelay time.Duration
+}
+
+// RetryInfo returns the retry info detail. If the error details do not contain a retry info detail, it returns an
+// invalid optional.
+func (xerr *Error) RetryInfo() Optional[RetryInfo] {
+	pb, err := xerr.findRetryInfo()
+	if errors.Is(err, errNotFound) {
+		return newInvalidOptional[RetryInfo]()
+	}
+	return newValidOptional(RetryInfo{RetryDelay: pb.GetRetryDelay().AsDuration()})
+}
+
 // ResourceInfos returns a list of resource info details. If the error details do not contain resource info details, it
 // returns nil.
 func (xerr *Error) ResourceInfos() []ResourceInfo {
@@ -464,6 +596,7 @@ func (xerr *Error) QuotaViolations() []QuotaViolation {
 
 // AddVar adds a variable to the runtime state.
 func (xerr *Error) AddVar(name string, value any) *Error {
+	xerr = xerr.mutable()
 	if name == "" || value == nil {
 		return xerr
 	}
@@ -473,29 +606,36 @@ func (xerr *Error) AddVar(name string, value any) *Error {
 
 // AddVars adds multiple variables to the runtime state.
 func (xerr *Error) AddVars(vars ...Var) *Error {
+	xerr = xerr.mutable()
 	for _, v := range vars {
-		_ = xerr.AddVar(v.Name, v.Value)
+		if v.Name == "" || v.Value == nil {
+			continue
+		}
+		xerr.runtimeState = append(xerr.runtimeState, v)
 	}
 	return xerr
 }
 
 // RuntimeState returns the runtime state of the error. This is used when you want to log the circumstances when the
-// error was encountered.
+// error was encountered. Note that the Value field holds the raw value, so use the SafeValue method when rendering it.
 func (xerr *Error) RuntimeState() []Var {
 	return xerr.runtimeState
 }
 
 // HideDetails marks the error as having hidden details. This is useful when you want to hide the details of the error
-// from external callers. Effectively, this means that the "debug info" and "error info" details are removed from the
+// from external callers. Effectively, this means that the "debug info" and "error info" details, as well as the
+// "request info" detail added by WithContext(), are removed from the
 // error when returned to the caller. For this to work, the server has to use the implementation-specific functionality
 // such as the unary interceptor for gRPC.
 func (xerr *Error) HideDetails() *Error {
+	xerr = xerr.mutable()
 	xerr.detailsHidden = true
 	return xerr
 }
 
 // ShowDetails marks the error as having shown details. This is the inverse of HideDetails.
 func (xerr *Error) ShowDetails() *Error {
+	xerr = xerr.mutable()
 	xerr.detailsHidden = false
 	return xerr
 }
@@ -507,6 +647,7 @@ func (xerr *Error) LogLevel() LogLevel {
 
 // SetLogLevel sets the log level of the error.
 func (xerr *Error) SetLogLevel(level LogLevel) *Error {
+	xerr = xerr.mutable()
 	xerr.logLevel = level
 	return xerr
 }
@@ -517,10 +658,7 @@ func (xerr *Error) IsDirectlyRetryable() bool {
 	if xerr == nil {
 		return false
 	}
-	if xerr.status.Code() == codes.Unavailable {
-		return true
-	}
-	return false
+	return CodeInfoOf(xerr.status.Code()).RetryClass == RetryClassDirect
 }
 
 // IsRetryableAtHigherLevel returns true if the call that caused the error cannot be directly retried, but instead
@@ -530,12 +668,7 @@ func (xerr *Error) IsRetryableAtHigherLevel() bool {
 	if xerr == nil {
 		return false
 	}
-	switch xerr.status.Code() {
-	case codes.ResourceExhausted, codes.Aborted:
-		return true
-	default:
-		return false
-	}
+	return CodeInfoOf(xerr.status.Code()).RetryClass == RetryClassHigherLevel
 }
 
 // IsDetailsHidden returns true if the error details are hidden, otherwise it returns false.
@@ -544,16 +677,20 @@ func (xerr *Error) IsDetailsHidden() bool {
 }
 
 // RemoveSensitiveDetails removes sensitive details from the error. This is useful when you want to return the error
-// to the client, but you don't want to expose sensitive details such as debug info or error info.
+// to the client, but you don't want to expose sensitive details such as debug info or error info. The request info
+// detail added by WithContext() is removed as well.
 func (xerr *Error) RemoveSensitiveDetails() *Error {
+	xerr = xerr.mutable()
 	// Find the indexes of the details that should be deleted
 	var deletingDetails []int
 	for i, detail := range xerr.status.Details() {
 		switch detail.(type) {
-		case *errdetails.DebugInfo, *errdetails.ErrorInfo:
+		case *errdetails.DebugInfo, *errdetails.ErrorInfo, *xerrorpb.Provenance:
 			deletingDetails = append(deletingDetails, i)
 		default:
-			continue
+			if xerr.isTraceRequestInfo(detail) {
+				deletingDetails = append(deletingDetails, i)
+			}
 		}
 	}
 
@@ -580,6 +717,7 @@ func (xerr *Error) RemoveSensitiveDetails() *Error {
 
 // SetStatus sets the status of the error.
 func (xerr *Error) SetStatus(s *status.Status) *Error {
+	xerr = xerr.mutable()
 	xerr.status = *s
 	return xerr
 }
@@ -602,6 +740,35 @@ func (xerr *Error) StatusMessage() string {
 	return xerr.status.Message()
 }
 
+// Is reports whether the error matches the target, which makes errors.Is work with Error instances through arbitrary
+// chains of wrapped errors, also after the error has been rebuilt, for example by xgrpc.ErrorFrom. The error matches if
+// target is an Error instance with the same status code and an ErrorInfo detail, and the error has an ErrorInfo detail
+// with the same domain and reason. Other details, the message and the runtime state are ignored. A target without an
+// ErrorInfo detail, ex. one created by NewInternal, only matches itself.
+//
+// Ex.
+//
+//	var ErrOutOfStock = xerror.NewSentinel(codes.ResourceExhausted, order.Domain, order.ReasonOutOfStock)
+//
+//	err := orderClientpb.OrderPencils(ctx, req)
+//	if errors.Is(xgrpc.ErrorFrom(err), ErrOutOfStock) {
+//		restockPencils()
+//	}
+func (xerr *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || t == nil {
+		return false
+	}
+	if xerr.StatusCode() != t.StatusCode() {
+		return false
+	}
+	info, err := t.findErrorInfo()
+	if errors.Is(err, errNotFound) {
+		return false
+	}
+	return xerr.IsDomainError(info.Domain, info.Reason)
+}
+
 // IsDomainError compares the error with the provided domain-specific error details (the domain and reason).
 // The reason is machine-readable and most importantly, it is unique within a particular domain of errors. This
 // method is used to check if a returned error is a particular domain-specific error. This is useful when decisions
@@ -645,35 +812,23 @@ func (xerr *Error) DomainType() string {
 	return DomainType(info.Domain, info.Reason)
 }
 
-// MarshalJSON marshals the error to JSON. This is only useful for testing purposes to be able to generate golden
-// files to be able to inspect the error in a human-readable format.
-func (xerr *Error) MarshalJSON() ([]byte, error) {
-	type marshallable struct {
-		LogLevel      LogLevel    `json:"logLevel"`
-		Status        *spb.Status `json:"status"`
-		DetailsHidden bool        `json:"detailsHidden"`
-		RuntimeState  []Var       `json:"runtimeState"`
-	}
-	err := marshallable{
-		LogLevel:      xerr.logLevel,
-		Status:        xerr.status.Proto(),
-		DetailsHidden: xerr.detailsHidden,
-		RuntimeState:  xerr.runtimeState,
-	}
-	return json.Marshal(err)
-}
-
 // WrappedError is a model that makes it easy to add more context to an error as it is passed up the call stack.
 type WrappedError struct {
 	Msg string
 	Err error
+	// vars is the runtime state added when the wrapped error isn't, and doesn't wrap, an Error instance.
+	vars []Var
 }
 
 func (wr *WrappedError) Error() string {
-	if wr.Err == nil {
+	switch {
+	case wr.Err == nil:
 		return wr.Msg
+	case wr.Msg == "":
+		return wr.Err.Error()
+	default:
+		return wr.Msg + ": " + wr.Err.Error()
 	}
-	return wr.Msg + ": " + wr.Err.Error()
 }
 
 // Unwrap returns the directly wrapped error.
@@ -681,29 +836,31 @@ func (wr *WrappedError) Unwrap() error {
 	return wr.Err
 }
 
-// AddVar adds runtime state information to the wrapped Error instance, if there is one.
+// AddVar adds runtime state information to the wrapped Error instance, if there is one. Otherwise, it's kept by the
+// WrappedError instance, and it's added to the Error instance created by From.
 func (wr *WrappedError) AddVar(name string, value any) *WrappedError {
-	if name == "" || value == nil {
-		return wr
-	}
-	var xerr *Error
-	if !errors.As(wr.Err, &wr) {
-		return wr
-	}
-	_ = xerr.AddVar(name, value)
-	return wr
+	return wr.AddVars(Var{Name: name, Value: value})
 }
 
-// AddVars adds multiple runtime state information to the wrapped Error instance, if there is one.
+// AddVars adds multiple runtime state information to the wrapped Error instance, if there is one. Otherwise, they're
+// kept by the WrappedError instance, and they're added to the Error instance created by From. They're also kept by the
+// WrappedError instance if it wraps a sentinel or several aggregated errors, see NewSentinel and Join.
 func (wr *WrappedError) AddVars(vars ...Var) *WrappedError {
 	if len(vars) == 0 {
 		return wr
 	}
-	var xerr *Error
-	if !errors.As(wr.Err, &wr) {
+	// Adding the variables to one of several aggregated errors would modify an error that the caller may still use
+	_, aggregated := asMulti(wr.Err)
+	if xerr := wr.XError(); xerr != nil && !xerr.sentinel && !aggregated {
+		_ = xerr.AddVars(vars...)
 		return wr
 	}
-	_ = xerr.AddVars(vars...)
+	for _, v := range vars {
+		if v.Name == "" || v.Value == nil {
+			continue
+		}
+		wr.vars = append(wr.vars, v)
+	}
 	return wr
 }
 
@@ -717,23 +874,99 @@ func (wr *WrappedError) XError() *Error {
 	return xerr
 }
 
+// Chain returns the messages of the chain of errors, from the outermost to the innermost, and the merged runtime state
+// of the chain. It is meant to be used when logging the error. Each error in the chain contributes its own message,
+// i.e. the Msg of a WrappedError, the message of the innermost error, and for any other wrapping error, ex. one created
+// with fmt.Errorf("handler: %w", err), the part of its message that precedes the message of the error it wraps.
+// Empty messages are left out. If the message of a wrapping error doesn't end with the message of the error it wraps,
+// then it is kept as a whole and the messages of the errors it wraps are left out. The runtime state is the one of the
+// wrapped Error instance, if there is one, followed by the variables kept by the WrappedError instances, from the
+// innermost to the outermost.
+//
+// Ex.
+//
+//	var wr *xerror.WrappedError
+//	if errors.As(err, &wr) {
+//		msgs, vars := wr.Chain()
+//		logger.Error(strings.Join(msgs, ": "), "vars", vars)
+//	}
+func (wr *WrappedError) Chain() ([]string, []Var) {
+	var msgs []string
+	var err error = wr
+	for err != nil {
+		next := errors.Unwrap(err)
+		if w, ok := err.(*WrappedError); ok {
+			if w.Msg != "" {
+				msgs = append(msgs, w.Msg)
+			}
+			err = next
+			continue
+		}
+		if next == nil {
+			msgs = append(msgs, err.Error())
+			break
+		}
+		prefix, found := strings.CutSuffix(err.Error(), next.Error())
+		if !found {
+			msgs = append(msgs, err.Error())
+			break
+		}
+		if prefix = strings.TrimRight(prefix, ": "); prefix != "" {
+			msgs = append(msgs, prefix)
+		}
+		err = next
+	}
+
+	var vars []Var
+	if xerr := wr.XError(); xerr != nil {
+		vars = append(vars, xerr.RuntimeState()...)
+	}
+	return msgs, append(vars, wrappedVars(wr)...)
+}
+
+// wrappedVars returns the variables kept by the WrappedError instances in the chain of err, from the innermost to the
+// outermost.
+func wrappedVars(err error) []Var {
+	var vars []Var
+	for ; err != nil; err = errors.Unwrap(err) {
+		if wr, ok := err.(*WrappedError); ok {
+			vars = append(wr.vars[:len(wr.vars):len(wr.vars)], vars...)
+		}
+	}
+	return vars
+}
+
 // Wrap wrap errors with a message to add more context to the error. It is used when receiving an error from a
-// call that is already an Error instance and you want to add more context to the error.
+// call that is already an Error instance and you want to add more context to the error. Any variables are added as
+// with WrappedError.AddVars.
 //
 // Ex.
 //
 //	 err := pkg.Func() // returns an Error instance
 //	 if err != nil {
-//		  return errors.Wrap(err, "more context to err")
+//		  return xerror.Wrap(err, "more context to err", xerror.Var{Name: "user_id", Value: userID})
 //	 }
-func Wrap(err error, msg string) error {
+func Wrap(err error, msg string, vars ...Var) error {
 	if err == nil {
 		return nil
 	}
-	if msg == "" {
+	if msg == "" && len(vars) == 0 {
 		return err
 	}
-	return &WrappedError{Msg: msg, Err: err}
+	return (&WrappedError{Msg: msg, Err: err}).AddVars(vars...)
+}
+
+// Wrapf is like Wrap, but the message is formatted according to a format specifier. Since err is already wrapped, the
+// %w verb isn't supported.
+//
+// Ex.
+//
+//	err := pkg.Func() // returns an Error instance
+//	if err != nil {
+//		return xerror.Wrapf(err, "failed to fetch user %q", userID)
+//	}
+func Wrapf(err error, format string, args ...any) error {
+	return Wrap(err, fmt.Sprintf(format, args...))
 }
 
 // DomainType returns a unique error type based on the domain and reason. This is used to enable switch-case statements.
@@ -744,15 +977,56 @@ func DomainType(domain, reason string) string {
 // From returns an Error instance from an error. It's meant to be used in your application, at the place in the code
 // where the error is logged.
 //
-// If the error is not an Error instance, then it is an unexpected error and should be logged, so it can be discovered
-// that there's code where the error isn't correctly handled.
+// If the error is, or wraps, an Error instance, then that instance is returned. Context errors are converted to
+// Cancelled and DeadlineExceeded errors respectively. Any other error is an unexpected error and is converted to an
+// Unknown error. It should be logged, so it can be discovered that there's code where the error isn't correctly
+// handled. The details of these errors are hidden, their status message is the default message of their status code,
+// see CodeInfo, and the message of the error is kept in their debug info detail. The runtime state of the new Error
+// instance is the one kept by the WrappedError instances in the chain of the error.
+//
+// If the error aggregates several errors, such as the errors returned by Join and errors.Join, then they are converted
+// to a single Error. Its status is the one of the error whose code has the highest precedence, where errors that won't
+// go away by retrying come before transient ones:
+//
+//	DataLoss, Internal, Unknown, Unimplemented, Unauthenticated, PermissionDenied, InvalidArgument, OutOfRange,
+//	FailedPrecondition, NotFound, AlreadyExists, ResourceExhausted, Aborted, Unavailable, DeadlineExceeded, Canceled
+//
+// The bad request, precondition, quota and resource info details of the other errors with the same code are merged
+// into it, unless their details are hidden. The runtime state of all the errors is kept, along with the variables
+// added when wrapping the aggregate, and the log level is the highest one among them. Since the Error is created on every call, changes to it don't affect the aggregated errors.
+//
+// If the error is nil, then nil is returned.
 func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if errs, ok := asMulti(err); ok {
+		xerr := aggregate(errs)
+		if xerr == nil {
+			return nil
+		}
+		// The variables added when wrapping the aggregate are kept by the wrapping errors, see WrappedError.AddVars
+		return xerr.AddVars(wrappedVars(err)...)
+	}
 	var xerr *Error
-	if !errors.As(err, &xerr) {
-		return &Error{
-			logLevel: LogLevelError,
-			status:   *status.New(codes.Unknown, err.Error()),
+	if errors.As(err, &xerr) {
+		if xerr.sentinel {
+			// The variables added when wrapping a sentinel are kept by the wrapping errors, see WrappedError.AddVars
+			return xerr.clone().AddVars(wrappedVars(err)...)
 		}
+		return xerr
+	}
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
 	}
+	// The error message may contain internal details, such as SQL statements or file paths, so it's kept in the hidden
+	// debug info rather than in the status message
+	xerr = maker.newErrorWithDetailsHidden(code, codeInfos[code].DefaultMessage, codeInfos[code].LogLevel)
+	_ = xerr.SetDebugInfo(err.Error(), nil)
+	xerr.runtimeState = wrappedVars(err)
 	return xerr
 }
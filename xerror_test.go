@@ -0,0 +1,365 @@
+package xerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type customWrapError struct {
+	msg string
+	err error
+}
+
+func (e *customWrapError) Error() string { return e.msg }
+
+func (e *customWrapError) Unwrap() error { return e.err }
+
+func TestWrappedError_AddVars(t *testing.T) {
+	type want struct {
+		xerrVars []Var
+		msgs     []string
+		vars     []Var
+	}
+	tests := []struct {
+		name string
+		err  func() (error, *Error)
+		want want
+	}{
+		{
+			name: "vars reach the wrapped xerror",
+			err: func() (error, *Error) {
+				xerr := NewInternal(errors.New("boom")).AddVar("a", 1)
+				err := Wrap(xerr, "inner", Var{Name: "b", Value: 2})
+				wr := Wrap(err, "outer").(*WrappedError)
+				_ = wr.AddVar("c", 3).AddVars(Var{Name: "d", Value: 4}, Var{Name: "", Value: 5})
+				return wr, xerr
+			},
+			want: want{
+				xerrVars: []Var{{Name: "a", Value: 1}, {Name: "b", Value: 2}, {Name: "c", Value: 3}, {Name: "d", Value: 4}},
+				msgs:     []string{"outer", "inner", "rpc error: code = Internal desc = boom"},
+				vars:     []Var{{Name: "a", Value: 1}, {Name: "b", Value: 2}, {Name: "c", Value: 3}, {Name: "d", Value: 4}},
+			},
+		},
+		{
+			name: "wrapping error whose message doesn't end with the wrapped one",
+			err: func() (error, *Error) {
+				err := &customWrapError{msg: "query failed (no rows)", err: errors.New("no rows")}
+				return Wrap(err, "outer"), nil
+			},
+			want: want{msgs: []string{"outer", "query failed (no rows)"}},
+		},
+		{
+			name: "vars are kept when the wrapped error isn't an xerror",
+			err: func() (error, *Error) {
+				err := Wrapf(errors.New("no rows"), "failed to fetch user %d", 1)
+				err = fmt.Errorf("handler: %w", Wrap(err, "", Var{Name: "a", Value: 1}))
+				wr := Wrap(err, "outer").(*WrappedError)
+				_ = wr.AddVar("b", 2)
+				return wr, nil
+			},
+			want: want{
+				msgs: []string{"outer", "handler", "failed to fetch user 1", "no rows"},
+				vars: []Var{{Name: "a", Value: 1}, {Name: "b", Value: 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			err, xerr := tt.err()
+
+			/* ---------------------------------- When ---------------------------------- */
+			msgs, vars := err.(*WrappedError).Chain()
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.msgs, msgs)
+			require.Equal(tt.want.vars, vars)
+			if xerr != nil {
+				require.Equal(tt.want.xerrVars, xerr.RuntimeState())
+				require.Same(xerr, From(err))
+				return
+			}
+			got := From(err)
+			require.Equal(codes.Unknown, got.StatusCode())
+			require.Equal(tt.want.vars, got.RuntimeState())
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	require := require.New(t)
+
+	require.Nil(Wrap(nil, "msg"))
+	require.Nil(Wrapf(nil, "msg %d", 1))
+
+	err := errors.New("boom")
+	require.Same(err, Wrap(err, ""))
+	require.EqualError(Wrapf(err, "failed to fetch user %d", 1), "failed to fetch user 1: boom")
+	require.EqualError(Wrap(err, "", Var{Name: "a", Value: 1}), "boom")
+	require.ErrorIs(Wrap(err, "msg"), err)
+}
+
+func TestError_Is(t *testing.T) {
+	errOutOfStock := NewSentinel(codes.ResourceExhausted, "order.example.com", "OUT_OF_STOCK")
+	outOfStock := func() *Error {
+		return new(Error).SetStatus(status.New(codes.ResourceExhausted, "the item is out of stock")).
+			SetErrorInfo("order.example.com", "OUT_OF_STOCK", map[string]any{"item": "pencil"})
+	}
+	internal := NewInternal(errors.New("boom"))
+
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{
+			name:   "same code, domain and reason",
+			err:    outOfStock(),
+			target: errOutOfStock,
+			want:   true,
+		},
+		{
+			name:   "wrapped error",
+			err:    fmt.Errorf("order failed: %w", Wrap(outOfStock(), "ordering pencils")),
+			target: errOutOfStock,
+			want:   true,
+		},
+		{
+			name:   "rebuilt from status",
+			err:    new(Error).SetStatus(outOfStock().Status()),
+			target: errOutOfStock,
+			want:   true,
+		},
+		{
+			name:   "joined error",
+			err:    Join(NewCancelled(), outOfStock()),
+			target: errOutOfStock,
+			want:   true,
+		},
+		{
+			name:   "different reason",
+			err:    outOfStock(),
+			target: NewSentinel(codes.ResourceExhausted, "order.example.com", "QUOTA_EXCEEDED"),
+		},
+		{
+			name:   "different domain",
+			err:    outOfStock(),
+			target: NewSentinel(codes.ResourceExhausted, "stock.example.com", "OUT_OF_STOCK"),
+		},
+		{
+			name:   "different code",
+			err:    outOfStock().SetStatus(status.New(codes.FailedPrecondition, "out of stock")),
+			target: errOutOfStock,
+		},
+		{
+			name:   "same instance without error info",
+			err:    fmt.Errorf("failed: %w", internal),
+			target: internal,
+			want:   true,
+		},
+		{
+			name:   "target without error info",
+			err:    NewInternal(errors.New("boom")),
+			target: NewInternal(errors.New("boom")),
+		},
+		{
+			name:   "error without error info",
+			err:    NewNotFound(ResourceInfo{ResourceType: "item"}),
+			target: NewSentinel(codes.NotFound, "order.example.com", "ITEM_NOT_FOUND"),
+		},
+		{
+			name:   "target isn't an xerror",
+			err:    outOfStock(),
+			target: errors.New("out of stock"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestFrom_NonXErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{
+			name: "unexpected error",
+			err:  errors.New("pq: relation \"users\" does not exist"),
+			want: codes.Unknown,
+		},
+		{
+			name: "context canceled",
+			err:  fmt.Errorf("calling the database: %w", context.Canceled),
+			want: codes.Canceled,
+		},
+		{
+			name: "context deadline exceeded",
+			err:  fmt.Errorf("calling the database: %w", context.DeadlineExceeded),
+			want: codes.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := From(tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want, got.StatusCode())
+			require.Equal(CodeInfoOf(tt.want).DefaultMessage, got.StatusMessage())
+			require.Equal(CodeInfoOf(tt.want).LogLevel, got.LogLevel())
+			require.True(got.IsDetailsHidden())
+			require.Equal(tt.err.Error(), got.DebugInfo().Value.Detail)
+
+			_ = got.RemoveSensitiveDetails()
+			require.NotContains(fmt.Sprint(got.StatusProto()), tt.err.Error())
+			require.False(got.DebugInfo().Valid)
+		})
+	}
+}
+
+func TestError_SetRetryInfo(t *testing.T) {
+	xerr := NewUnavailable(errors.New("connection refused"))
+	require.False(t, xerr.RetryInfo().Valid)
+
+	_ = xerr.SetRetryInfo(0)
+	require.False(t, xerr.RetryInfo().Valid)
+
+	_ = xerr.SetRetryInfo(time.Second).SetRetryInfo(time.Minute)
+	require.Equal(t, Optional[RetryInfo]{Valid: true, Value: RetryInfo{RetryDelay: time.Minute}}, xerr.RetryInfo())
+	require.Len(t, xerr.StatusProto().GetDetails(), 1)
+}
+
+func TestError_ExistingDetailsAreUpdated(t *testing.T) {
+	tests := []struct {
+		name  string
+		xerr  func() *Error
+		check func(t *testing.T, xerr *Error)
+	}{
+		{
+			name: "bad request violations",
+			xerr: func() *Error {
+				return NewInvalidArgument("name", "must not be empty").
+					AddBadRequestViolations([]BadRequestViolation{{Field: "age", Description: "must be positive"}})
+			},
+			check: func(t *testing.T, xerr *Error) {
+				require.Equal(t, []BadRequestViolation{
+					{Field: "name", Description: "must not be empty"},
+					{Field: "age", Description: "must be positive"},
+				}, xerr.BadRequestViolations())
+			},
+		},
+		{
+			name: "precondition violations",
+			xerr: func() *Error {
+				return NewPreconditionFailure("users/1", "TOS", "terms not accepted").
+					AddPreconditionViolations([]PreconditionViolation{{Subject: "users/1", Typ: "AGE", Description: "too young"}})
+			},
+			check: func(t *testing.T, xerr *Error) {
+				require.Len(t, xerr.PreconditionViolations(), 2)
+			},
+		},
+		{
+			name: "quota violations",
+			xerr: func() *Error {
+				return NewQuotaFailure("project:1", "daily limit exceeded").
+					AddQuotaViolations([]QuotaViolation{{Subject: "project:1", Description: "hourly limit exceeded"}})
+			},
+			check: func(t *testing.T, xerr *Error) {
+				require.Len(t, xerr.QuotaViolations(), 2)
+			},
+		},
+		{
+			name: "error info",
+			xerr: func() *Error {
+				return NewInternal(nil).
+					SetErrorInfo("a.example.com", "FIRST", nil).
+					SetErrorInfo("b.example.com", "SECOND", map[string]any{"k": 1})
+			},
+			check: func(t *testing.T, xerr *Error) {
+				require.Equal(t, Optional[ErrorInfo]{Valid: true, Value: ErrorInfo{
+					Domain: "b.example.com", Reason: "SECOND", Metadata: map[string]string{"k": "1"},
+				}}, xerr.ErrorInfo())
+			},
+		},
+		{
+			name: "debug info",
+			xerr: func() *Error {
+				return NewInternal(nil).SetDebugInfo("first", nil).SetDebugInfo("second", []string{"main.go:1"})
+			},
+			check: func(t *testing.T, xerr *Error) {
+				require.Equal(t, Optional[DebugInfo]{Valid: true, Value: DebugInfo{
+					Detail: "second", StackEntries: []string{"main.go:1"},
+				}}, xerr.DebugInfo())
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			xerr := tt.xerr()
+			tt.check(t, xerr)
+			// Updating a detail must not add another one of the same type
+			types := map[string]bool{}
+			for _, detail := range xerr.StatusProto().GetDetails() {
+				require.False(t, types[detail.GetTypeUrl()], detail.GetTypeUrl())
+				types[detail.GetTypeUrl()] = true
+			}
+		})
+	}
+}
+
+func TestNewSentinel_IsNeverModified(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	errOutOfStock := NewSentinel(codes.ResourceExhausted, "order.example.com", "OUT_OF_STOCK")
+	want := errOutOfStock.StatusProto()
+	ctx := WithVars(context.Background(), Var{Name: "tenant_id", Value: "tenant-1"})
+
+	/* ---------------------------------- When ---------------------------------- */
+	added := errOutOfStock.AddVar("sku", "pencil").HideDetails()
+	wrapped := From(Wrap(errOutOfStock, "ordering pencils", Var{Name: "quantity", Value: 2}))
+	responded := From(errOutOfStock).WithContext(ctx).RemoveSensitiveDetails()
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.NotSame(errOutOfStock, added)
+	require.Equal([]Var{{Name: "sku", Value: "pencil"}}, added.RuntimeState())
+	require.True(added.IsDetailsHidden())
+	require.Equal([]Var{{Name: "quantity", Value: 2}}, wrapped.RuntimeState())
+	require.Equal([]Var{{Name: "tenant_id", Value: "tenant-1"}}, responded.RuntimeState())
+	require.False(responded.ErrorInfo().Valid)
+	for _, err := range []*Error{added, wrapped} {
+		require.ErrorIs(err, errOutOfStock)
+		// Copies are regular errors, which are modified in place
+		require.Same(err, err.AddVar("k", "v"))
+	}
+
+	require.Empty(errOutOfStock.RuntimeState())
+	require.False(errOutOfStock.IsDetailsHidden())
+	require.True(proto.Equal(want, errOutOfStock.StatusProto()))
+}
+
+func TestNewSentinel(t *testing.T) {
+	errOutOfStock := NewSentinel(codes.ResourceExhausted, "order.example.com", "OUT_OF_STOCK")
+	require.Equal(t, CodeInfoOf(codes.ResourceExhausted).LogLevel, errOutOfStock.LogLevel())
+	require.Equal(t, CodeInfoOf(codes.ResourceExhausted).LogLevel, From(errOutOfStock).LogLevel())
+	require.Equal(t, "order.example.com", errOutOfStock.ErrorInfo().Value.Domain)
+
+	require.PanicsWithError(t, `domain "", reason "OUT_OF_STOCK": `+ErrInvalidSentinel.Error(), func() {
+		NewSentinel(codes.ResourceExhausted, "", "OUT_OF_STOCK")
+	})
+	require.Panics(t, func() { NewSentinel(codes.ResourceExhausted, "order.example.com", "") })
+}
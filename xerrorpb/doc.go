@@ -0,0 +1,5 @@
+// Package xerrorpb contains the Go code generated from proto/xerror/v1/xerror.proto. The ErrorEnvelope message carries
+// a complete xerror, see xerror.Error.Envelope and xerror.FromEnvelope.
+package xerrorpb
+
+//go:generate buf generate --template ../buf.gen.yaml ../proto
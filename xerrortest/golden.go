@@ -0,0 +1,100 @@
+package xerrortest
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/golden"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// NormalisedTraceID replaces the trace ID in golden files.
+	NormalisedTraceID = "--* TRACE ID *--"
+	// NormalisedSpanID replaces the span ID in golden files.
+	NormalisedSpanID = "--* SPAN ID *--"
+	// NormalisedStackEntry replaces the stack entries of debug info details in golden files.
+	NormalisedStackEntry = "--* STACK ENTRY *--"
+)
+
+// goldenError is how an xerror is rendered in golden files.
+type goldenError struct {
+	LogLevel      string               `json:"logLevel"`
+	Status        any                  `json:"status"`
+	DetailsHidden bool                 `json:"detailsHidden"`
+	RuntimeState  []xerror.Var         `json:"runtimeState"`
+	TraceContext  *xerror.TraceContext `json:"traceContext,omitempty"`
+}
+
+// Golden compares err with the golden file at path, see github.com/tobbstr/golden. Run the test with the -update flag
+// to update the golden file.
+//
+// The error must be, or wrap, an xerror. Its status is rendered in the protobuf JSON format, and the fields that change
+// between test runs are normalised. The trace and span IDs are replaced by NormalisedTraceID and NormalisedSpanID,
+// wherever they occur, and the stack entries of debug info details are replaced by NormalisedStackEntry. The runtime
+// state is sorted by name. Any other fields to skip can be given as paths, ex. "runtimeState.0.Value".
+func Golden(t *testing.T, path string, err error, skipFields ...string) {
+	t.Helper()
+	var xerr *xerror.Error
+	if !errors.As(err, &xerr) {
+		require.Failf(t, "want an xerror", "got %T: %v", err, err)
+	}
+
+	b, marshalErr := protojson.Marshal(xerr.StatusProto())
+	require.NoError(t, marshalErr, "failed to marshal status")
+	var status any
+	require.NoError(t, json.Unmarshal(b, &status), "failed to unmarshal status")
+
+	got := goldenError{
+		LogLevel:      xerr.LogLevel().String(),
+		DetailsHidden: xerr.IsDetailsHidden(),
+		RuntimeState:  slices.Clone(xerr.RuntimeState()),
+	}
+	var replacer *strings.Replacer
+	if tc := xerr.TraceContext(); tc.Valid {
+		got.TraceContext = &xerror.TraceContext{TraceID: NormalisedTraceID, SpanID: NormalisedSpanID}
+		replacer = strings.NewReplacer(tc.Value.TraceID, NormalisedTraceID, tc.Value.SpanID, NormalisedSpanID)
+	}
+	got.Status = normalise(status, replacer)
+	slices.SortStableFunc(got.RuntimeState, func(a, b xerror.Var) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	golden.JSON(t, path, got, skipFields...)
+}
+
+// normalise replaces the values of v that change between test runs.
+func normalise(v any, replacer *strings.Replacer) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == "stackEntries" {
+				if entries, ok := value.([]any); ok {
+					for i := range entries {
+						entries[i] = NormalisedStackEntry
+					}
+				}
+				continue
+			}
+			v[key] = normalise(value, replacer)
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = normalise(value, replacer)
+		}
+		return v
+	case string:
+		if replacer == nil {
+			return v
+		}
+		return replacer.Replace(v)
+	default:
+		return v
+	}
+}
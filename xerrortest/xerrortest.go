@@ -0,0 +1,221 @@
+/*
+Package xerrortest provides helpers for asserting on xerrors in tests. It has matchers for the properties of an xerror,
+a semantic equality check and a golden-file helper. The helpers work with *testing.T and with testify, either by
+passing the testing.T, or by asserting on the error returned by Check and Compare.
+
+Ex.
+
+	err := svc.CreateContact(ctx, req)
+	xerrortest.Require(t, err, xerrortest.HasCode(codes.InvalidArgument), xerrortest.HasFieldViolation("fullName"))
+
+	// or with testify
+	require.NoError(t, xerrortest.Check(err, xerrortest.HasCode(codes.InvalidArgument)))
+*/
+package xerrortest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestingT is the subset of *testing.T that is used by the assertion helpers. It is also satisfied by the TestingT of
+// testify's require package.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	FailNow()
+}
+
+// Matcher checks a property of an xerror. It returns nil if the xerror matches, otherwise it returns an error that
+// describes the mismatch.
+type Matcher func(xerr *xerror.Error) error
+
+// HasCode matches xerrors with the status code.
+func HasCode(code codes.Code) Matcher {
+	return func(xerr *xerror.Error) error {
+		if got := xerr.StatusCode(); got != code {
+			return fmt.Errorf("want code %s, got %s", code, got)
+		}
+		return nil
+	}
+}
+
+// HasMessage matches xerrors with the status message.
+func HasMessage(msg string) Matcher {
+	return func(xerr *xerror.Error) error {
+		if got := xerr.StatusMessage(); got != msg {
+			return fmt.Errorf("want message %q, got %q", msg, got)
+		}
+		return nil
+	}
+}
+
+// HasDomainReason matches xerrors with an ErrorInfo detail with the domain and reason.
+func HasDomainReason(domain, reason string) Matcher {
+	return func(xerr *xerror.Error) error {
+		info := xerr.ErrorInfo()
+		if !info.Valid {
+			return fmt.Errorf("want error info with domain %q and reason %q, got no error info", domain, reason)
+		}
+		if !xerr.IsDomainError(domain, reason) {
+			return fmt.Errorf("want error info with domain %q and reason %q, got domain %q and reason %q",
+				domain, reason, info.Value.Domain, info.Value.Reason)
+		}
+		return nil
+	}
+}
+
+// HasFieldViolation matches xerrors with a bad request violation of the field.
+func HasFieldViolation(field string) Matcher {
+	return func(xerr *xerror.Error) error {
+		violations := xerr.BadRequestViolations()
+		fields := make([]string, len(violations))
+		for i, v := range violations {
+			if v.Field == field {
+				return nil
+			}
+			fields[i] = v.Field
+		}
+		return fmt.Errorf("want bad request violation of field %q, got violations of fields [%s]", field,
+			strings.Join(fields, ", "))
+	}
+}
+
+// HasResourceInfo matches xerrors with a resource info of the resource type and name.
+func HasResourceInfo(typ, name string) Matcher {
+	return func(xerr *xerror.Error) error {
+		infos := xerr.ResourceInfos()
+		resources := make([]string, len(infos))
+		for i, info := range infos {
+			if info.ResourceType == typ && info.ResourceName == name {
+				return nil
+			}
+			resources[i] = info.ResourceType + " " + info.ResourceName
+		}
+		return fmt.Errorf("want resource info of %s %s, got resource infos of [%s]", typ, name,
+			strings.Join(resources, ", "))
+	}
+}
+
+// HasVar matches xerrors with a runtime state variable with the name and value.
+func HasVar(name string, value any) Matcher {
+	return func(xerr *xerror.Error) error {
+		for _, v := range xerr.RuntimeState() {
+			if v.Name == name && reflect.DeepEqual(v.Value, value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("want runtime state variable %s=%v, got %v", name, value, xerr.RuntimeState())
+	}
+}
+
+// Check returns nil if err is, or wraps, an xerror that satisfies all matchers. Otherwise, it returns an error that
+// describes all mismatches.
+func Check(err error, matchers ...Matcher) error {
+	var xerr *xerror.Error
+	if !errors.As(err, &xerr) {
+		return fmt.Errorf("want an xerror, got %T: %v", err, err)
+	}
+	var mismatches []error
+	for _, match := range matchers {
+		if mismatch := match(xerr); mismatch != nil {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return errors.Join(mismatches...)
+}
+
+// Assert reports an error to t unless err satisfies all matchers. It returns true if it does. See Check.
+func Assert(t TestingT, err error, matchers ...Matcher) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if mismatch := Check(err, matchers...); mismatch != nil {
+		t.Errorf("xerror mismatch:\n%v", mismatch)
+		return false
+	}
+	return true
+}
+
+// Require is like Assert, but it stops the test unless err satisfies all matchers.
+func Require(t TestingT, err error, matchers ...Matcher) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if !Assert(t, err, matchers...) {
+		t.FailNow()
+	}
+}
+
+// Compare returns nil if want and got are semantically equal xerrors. Otherwise, it returns an error that describes the
+// differences. They are equal if their statuses, log levels, details visibility and trace contexts are equal, and if
+// their runtime states contain the same variables, in any order.
+func Compare(want, got error) error {
+	var wantXErr, gotXErr *xerror.Error
+	if !errors.As(want, &wantXErr) {
+		return fmt.Errorf("want must be an xerror, got %T: %v", want, want)
+	}
+	if !errors.As(got, &gotXErr) {
+		return fmt.Errorf("want an xerror, got %T: %v", got, got)
+	}
+
+	var diffs []error
+	if !proto.Equal(wantXErr.StatusProto(), gotXErr.StatusProto()) {
+		diffs = append(diffs, fmt.Errorf("want status %s, got %s",
+			protojson.Format(wantXErr.StatusProto()), protojson.Format(gotXErr.StatusProto())))
+	}
+	if wantXErr.LogLevel() != gotXErr.LogLevel() {
+		diffs = append(diffs, fmt.Errorf("want log level %s, got %s", wantXErr.LogLevel(), gotXErr.LogLevel()))
+	}
+	if wantXErr.IsDetailsHidden() != gotXErr.IsDetailsHidden() {
+		diffs = append(diffs, fmt.Errorf("want details hidden %t, got %t",
+			wantXErr.IsDetailsHidden(), gotXErr.IsDetailsHidden()))
+	}
+	if wantXErr.TraceContext() != gotXErr.TraceContext() {
+		diffs = append(diffs, fmt.Errorf("want trace context %+v, got %+v",
+			wantXErr.TraceContext(), gotXErr.TraceContext()))
+	}
+	if !sameVars(wantXErr.RuntimeState(), gotXErr.RuntimeState()) {
+		diffs = append(diffs, fmt.Errorf("want runtime state %v, got %v",
+			wantXErr.RuntimeState(), gotXErr.RuntimeState()))
+	}
+	return errors.Join(diffs...)
+}
+
+// Equal reports an error to t unless want and got are semantically equal xerrors. It returns true if they are. See
+// Compare.
+func Equal(t TestingT, want, got error) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if diff := Compare(want, got); diff != nil {
+		t.Errorf("xerrors not equal:\n%v", diff)
+		return false
+	}
+	return true
+}
+
+// sameVars returns true if a and b contain the same variables, in any order.
+func sameVars(a, b []xerror.Var) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	used := make([]bool, len(b))
+outer:
+	for _, va := range a {
+		for i, vb := range b {
+			if !used[i] && reflect.DeepEqual(va, vb) {
+				used[i] = true
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package xerrortest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+// fakeT records the failures reported by the helpers.
+type fakeT struct {
+	errors []string
+	failed bool
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) FailNow() {
+	t.failed = true
+}
+
+func TestCheck(t *testing.T) {
+	xerror.Init("myservice.example.com")
+	notFound := xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"}).
+		AddVar("user_id", 1)
+	invalidArgument := xerror.NewInvalidArgumentBatch([]xerror.BadRequestViolation{
+		{Field: "fullName", Description: "must be set"},
+		{Field: "emailAddresses[1].email", Description: "must be a valid email address"},
+	})
+	aborted := xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"})
+
+	tests := []struct {
+		name     string
+		err      error
+		matchers []Matcher
+		wantErr  bool
+	}{
+		{
+			name:     "matching code, resource info and var",
+			err:      fmt.Errorf("wrapped: %w", notFound),
+			matchers: []Matcher{HasCode(codes.NotFound), HasResourceInfo("user", "users/1"), HasVar("user_id", 1)},
+		},
+		{
+			name:     "mismatching resource info",
+			err:      notFound,
+			matchers: []Matcher{HasResourceInfo("user", "users/2")},
+			wantErr:  true,
+		},
+		{
+			name:     "matching field violation",
+			err:      invalidArgument,
+			matchers: []Matcher{HasCode(codes.InvalidArgument), HasFieldViolation("emailAddresses[1].email")},
+		},
+		{
+			name:     "mismatching field violation",
+			err:      invalidArgument,
+			matchers: []Matcher{HasFieldViolation("age")},
+			wantErr:  true,
+		},
+		{
+			name:     "matching domain and reason",
+			err:      aborted,
+			matchers: []Matcher{HasDomainReason("myservice.example.com", "VERSION_MISMATCH"), HasMessage("conflict")},
+		},
+		{
+			name:     "missing error info",
+			err:      notFound,
+			matchers: []Matcher{HasDomainReason("myservice.example.com", "VERSION_MISMATCH")},
+			wantErr:  true,
+		},
+		{
+			name:    "not an xerror",
+			err:     errors.New("boom"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			err := Check(tt.err, tt.matchers...)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	ft := &fakeT{}
+	Require(ft, xerror.NewCancelled(), HasCode(codes.Canceled))
+	require.False(t, ft.failed)
+
+	Require(ft, xerror.NewCancelled(), HasCode(codes.Internal), HasMessage("other"))
+	require.True(t, ft.failed)
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], "want code Internal, got Canceled")
+	require.Contains(t, ft.errors[0], `want message "other"`)
+}
+
+func TestEqual(t *testing.T) {
+	newErr := func(vars ...xerror.Var) *xerror.Error {
+		return xerror.NewInvalidArgument("fullName", "must be set").AddVars(vars...)
+	}
+	a, b := xerror.Var{Name: "a", Value: 1}, xerror.Var{Name: "b", Value: "2"}
+
+	tests := []struct {
+		name string
+		want error
+		got  error
+		ok   bool
+	}{
+		{
+			name: "runtime state in any order",
+			want: newErr(a, b),
+			got:  fmt.Errorf("wrapped: %w", newErr(b, a)),
+			ok:   true,
+		},
+		{
+			name: "different runtime state",
+			want: newErr(a, b),
+			got:  newErr(a, a),
+		},
+		{
+			name: "different log level",
+			want: newErr(),
+			got:  newErr().SetLogLevel(xerror.LogLevelWarn),
+		},
+		{
+			name: "different details",
+			want: newErr(),
+			got:  xerror.NewInvalidArgument("fullName", "must not be empty"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			ft := &fakeT{}
+			got := Equal(ft, tt.want, tt.got)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.Equal(t, tt.ok, got)
+			require.Equal(t, tt.ok, len(ft.errors) == 0)
+		})
+	}
+}
+
+func TestGolden(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	xerror.InitTraceContext(xerror.TraceContextOptions{
+		Extract: func(ctx context.Context) (xerror.TraceContext, bool) {
+			return xerror.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"}, true
+		},
+		Exposure: xerror.TraceExposureDebugInfo,
+	})
+	t.Cleanup(func() { xerror.InitTraceContext(xerror.TraceContextOptions{}) })
+
+	xerr := xerror.NewInternal(errors.New("boom")).
+		ShowDetails().
+		SetDebugInfo("failed to connect to the database", []string{"main.go:12", "db.go:34"}).
+		AddVar("user_id", 1).
+		AddVar("attempt", 3)
+
+	/* ---------------------------------- When ---------------------------------- */
+	err := xerr.WithContext(context.Background())
+
+	/* ---------------------------------- Then ---------------------------------- */
+	Golden(t, "testdata/golden/internal.json", err)
+}
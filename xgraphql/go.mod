@@ -0,0 +1,19 @@
+module github.com/tobbstr/xerror/xgraphql
+
+go 1.22.0
+
+require (
+	github.com/stretchr/testify v1.9.0
+	github.com/tobbstr/xerror v0.1.0
+	github.com/vektah/gqlparser/v2 v2.5.16
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240513163218-0867130af1f8 // indirect
+	google.golang.org/grpc v1.64.0 // indirect
+	google.golang.org/protobuf v1.34.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
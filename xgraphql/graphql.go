@@ -0,0 +1,117 @@
+/*
+Package xgraphql converts xerrors to GraphQL errors, see https://spec.graphql.org/October2021/#sec-Errors. The errors are
+of the gqlerror.Error type, which is the one used by gqlgen.
+*/
+package xgraphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/tobbstr/xerror"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// FieldViolation is a bad request violation in the extensions of a GraphQL error.
+type FieldViolation struct {
+	// Path is the field of the violation as a GraphQL path, ex. ["input", "emailAddresses", 1, "email"].
+	Path ast.Path `json:"path"`
+	// Field is the field of the violation as given in the bad request detail, ex. "input.emailAddresses[1].email".
+	Field string `json:"field"`
+	// Description describes why the field is bad.
+	Description string `json:"description"`
+}
+
+// GraphQLErrorFrom converts the error to a GraphQL error that's returned to a caller. The path is the path of the
+// response field that the error is for, if any. The extensions of the GraphQL error hold:
+//   - code: the status code, ex. 5
+//   - status: the status code name, ex. "NOT_FOUND"
+//   - domain and reason: the domain and reason of the error info detail, if any
+//   - fieldViolations: the bad request violations, if any, see FieldViolation
+//
+// The error is redacted just like xhttp.RespondFailedNegotiated does. It's converted using xerror.From if it isn't an
+// xerror, it's enriched with the values carried by ctx (see xerror.Error.WithContext), and it's passed to the observers
+// registered with xerror.RegisterObserver. Sensitive details are removed if the error is marked as hidden, which means
+// that the domain and reason aren't included.
+//
+// Ex. with gqlgen
+//
+//	srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
+//	  return xgraphql.GraphQLErrorFrom(ctx, err, graphql.GetPath(ctx))
+//	})
+func GraphQLErrorFrom(ctx context.Context, err error, path ast.Path) *gqlerror.Error {
+	xerr := xerror.From(err)
+	if xerr == nil {
+		xerr = xerror.From(errors.New("nil error received"))
+	}
+	_ = xerr.WithContext(ctx)
+	xerror.ObserveResponded(ctx, xerr)
+	if xerr.IsDetailsHidden() {
+		_ = xerr.RemoveSensitiveDetails()
+	}
+	_ = xerr.RemoveProvenance()
+
+	extensions := map[string]any{
+		"code":   int(xerr.StatusCode()),
+		"status": xerror.CodeInfoOf(xerr.StatusCode()).Name,
+	}
+	if info := xerr.ErrorInfo(); info.Valid {
+		extensions["domain"] = info.Value.Domain
+		extensions["reason"] = info.Value.Reason
+	}
+	if violations := xerr.BadRequestViolations(); len(violations) > 0 {
+		fieldViolations := make([]FieldViolation, len(violations))
+		for i, v := range violations {
+			fieldViolations[i] = FieldViolation{Path: pathFrom(v.Field), Field: v.Field, Description: v.Description}
+		}
+		extensions["fieldViolations"] = fieldViolations
+	}
+	return &gqlerror.Error{
+		Err:        xerr,
+		Message:    xerr.StatusMessage(),
+		Path:       path,
+		Extensions: extensions,
+	}
+}
+
+// pathFrom converts a field path, as rendered by xerror.FieldPath, to a GraphQL path. Indexes become path indexes and
+// quoted map keys become path names, ex. `emailAddresses[1].labels["team"]` becomes ["emailAddresses", 1, "labels",
+// "team"].
+func pathFrom(field string) ast.Path {
+	var path ast.Path
+	for field != "" {
+		switch field[0] {
+		case '.':
+			field = field[1:]
+		case '[':
+			end := strings.IndexByte(field, ']')
+			if quoted, err := strconv.QuotedPrefix(field[1:]); err == nil {
+				end = 1 + len(quoted)
+			}
+			if end < 0 || end >= len(field) || field[end] != ']' {
+				// Malformed subscripts are kept as they are
+				return append(path, ast.PathName(field))
+			}
+			subscript := field[1:end]
+			if key, err := strconv.Unquote(subscript); err == nil {
+				path = append(path, ast.PathName(key))
+			} else if i, err := strconv.Atoi(subscript); err == nil {
+				path = append(path, ast.PathIndex(i))
+			} else {
+				path = append(path, ast.PathName(subscript))
+			}
+			field = field[end+1:]
+		default:
+			end := strings.IndexAny(field, ".[")
+			if end < 0 {
+				end = len(field)
+			}
+			path = append(path, ast.PathName(field[:end]))
+			field = field[end:]
+		}
+	}
+	return path
+}
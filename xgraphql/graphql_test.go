@@ -0,0 +1,105 @@
+package xgraphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestGraphQLErrorFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	tests := []struct {
+		name string
+		err  error
+		path ast.Path
+		want string
+	}{
+		{
+			name: "field violations",
+			err: xerror.NewInvalidArgumentBatch([]xerror.BadRequestViolation{
+				{Field: "input.fullName", Description: "must be set"},
+				{Field: `input.emailAddresses[1].labels["team.name"]`, Description: "must not be empty"},
+			}),
+			path: ast.Path{ast.PathName("createUser")},
+			want: `{
+				"message": "one or more request arguments were invalid",
+				"path": ["createUser"],
+				"extensions": {
+					"code": 3,
+					"status": "INVALID_ARGUMENT",
+					"fieldViolations": [
+						{"path": ["input", "fullName"], "field": "input.fullName", "description": "must be set"},
+						{
+							"path": ["input", "emailAddresses", 1, "labels", "team.name"],
+							"field": "input.emailAddresses[1].labels[\"team.name\"]",
+							"description": "must not be empty"
+						}
+					]
+				}
+			}`,
+		},
+		{
+			name: "domain and reason",
+			err:  xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}),
+			want: `{
+				"message": "conflict",
+				"extensions": {
+					"code": 10,
+					"status": "ABORTED",
+					"domain": "myservice.example.com",
+					"reason": "VERSION_MISMATCH"
+				}
+			}`,
+		},
+		{
+			name: "hidden details",
+			err: xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+				HideDetails(),
+			want: `{"message": "conflict", "extensions": {"code": 10, "status": "ABORTED"}}`,
+		},
+		{
+			name: "canceled",
+			err:  context.Canceled,
+			want: `{"message": "request cancelled by the client", "extensions": {"code": 1, "status": "CANCELLED"}}`,
+		},
+		{
+			name: "non-xerror",
+			err:  errors.New("boom"),
+			want: `{"message": "something unknown happened", "extensions": {"code": 2, "status": "UNKNOWN"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := GraphQLErrorFrom(context.Background(), tt.err, tt.path)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			b, err := json.Marshal(got)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestPathFrom(t *testing.T) {
+	tests := []struct {
+		field string
+		want  ast.Path
+	}{
+		{field: "fullName", want: ast.Path{ast.PathName("fullName")}},
+		{field: "type[2]", want: ast.Path{ast.PathName("type"), ast.PathIndex(2)}},
+		{field: `labels["a]b"].value`, want: ast.Path{ast.PathName("labels"), ast.PathName("a]b"), ast.PathName("value")}},
+		{field: "labels[team", want: ast.Path{ast.PathName("labels"), ast.PathName("[team")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			require.Equal(t, tt.want, pathFrom(tt.field))
+		})
+	}
+}
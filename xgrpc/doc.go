@@ -0,0 +1,14 @@
+// Package xgrpc integrates xerrors with gRPC. Servers return xerrors from their handlers and register
+// UnaryXErrorInterceptor, or an interceptor returned by NewUnaryXErrorInterceptor, which responds with their
+// google.rpc.Status. Clients convert the returned errors back to xerrors using ErrorFrom or
+// UnaryClientXErrorInterceptor.
+//
+// The interceptors don't record errors on spans themselves, since this package doesn't depend on OpenTelemetry. The
+// errors returned by the server interceptors are passed to the observers registered with xerror.RegisterObserver
+// though, so they are recorded on the server spans by registering the trace observer of the xotel module at
+// startup-time, given that the spans are started by an instrumentation such as otelgrpc.
+//
+// Ex.
+//
+//	xerror.RegisterObserver(xotel.NewTraceObserver(xotel.TraceOptions{}), xerror.ObserverOptions{})
+package xgrpc
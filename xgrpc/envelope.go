@@ -0,0 +1,42 @@
+package xgrpc
+
+import (
+	"github.com/tobbstr/xerror/xerrorpb"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// withEnvelope returns a copy of the status with the envelope appended to its details. If env is nil, or if it can't be
+// appended, then the status is returned as-is.
+func withEnvelope(st *status.Status, env *xerrorpb.ErrorEnvelope) *status.Status {
+	if env == nil {
+		return st
+	}
+	withEnv, err := st.WithDetails(env)
+	if err != nil {
+		return st
+	}
+	return withEnv
+}
+
+// envelopeFrom returns the status without any envelope detail, and the envelope, if there is one.
+func envelopeFrom(st *status.Status) (*status.Status, *xerrorpb.ErrorEnvelope) {
+	pb := st.Proto()
+	var env *xerrorpb.ErrorEnvelope
+	details := make([]*anypb.Any, 0, len(pb.GetDetails()))
+	for _, detail := range pb.GetDetails() {
+		if !detail.MessageIs((*xerrorpb.ErrorEnvelope)(nil)) {
+			details = append(details, detail)
+			continue
+		}
+		candidate := &xerrorpb.ErrorEnvelope{}
+		if err := detail.UnmarshalTo(candidate); err == nil {
+			env = candidate
+		}
+	}
+	if env == nil {
+		return st, nil
+	}
+	return status.FromProto(&spb.Status{Code: pb.GetCode(), Message: pb.GetMessage(), Details: details}), env
+}
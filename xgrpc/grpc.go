@@ -5,33 +5,102 @@ import (
 	"errors"
 
 	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xerrorpb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 )
 
+// Options controls how xerrors are converted by gRPC clients.
+type Options struct {
+	// RecordProvenance makes UnaryClientXErrorInterceptor record the called method as a hop in the provenance of the
+	// errors it returns, see xerror.Error.AddHop. The hop consists of the domain of this service, see xerror.Init, the
+	// method and the status code.
+	RecordProvenance bool
+}
+
+var options Options
+
+// Init configures how xerrors are converted by gRPC clients.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func Init(opts Options) {
+	options = opts
+}
+
+// ServerOptions controls how xerrors are returned by a gRPC server, see NewUnaryXErrorInterceptor.
+type ServerOptions struct {
+	// AttachEnvelope makes the interceptor attach the complete xerror as an additional status detail, see
+	// xerrorpb.ErrorEnvelope, so that ErrorFrom can restore its log level and runtime state in the calling service.
+	//
+	// The envelope is attached before sensitive details are removed, which means that it carries everything about the
+	// error. It must only be enabled for servers whose callers are trusted internal services. For the same reason, the
+	// provenance of the error, see xerror.Error.AddHop, is only kept in the status if it is enabled.
+	AttachEnvelope bool
+}
+
 // UnaryXErrorInterceptor is a gRPC server unary interceptor that unwraps the XError and returns the wrapped
-// error status. It also removes sensitive details from errors if they are marked as hidden.
+// error status. It also removes sensitive details from errors if they are marked as hidden. Before that, the error is
+// enriched with the values carried by ctx (see xerror.Error.WithContext), and it is passed to the observers registered
+// with xerror.RegisterObserver. It never attaches the complete error as an envelope detail, and it always removes the
+// provenance of the error, see NewUnaryXErrorInterceptor.
 //
-// This interceptor must be used by gRPC servers if they are returning xerrors.
+// This interceptor, or one returned by NewUnaryXErrorInterceptor, must be used by gRPC servers if they are returning
+// xerrors.
 func UnaryXErrorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-	// Call the handler
+	return unaryXErrorInterceptor(ctx, req, handler, ServerOptions{})
+}
+
+// NewUnaryXErrorInterceptor returns a gRPC server unary interceptor that works like UnaryXErrorInterceptor, but is
+// configured by opts. Since the options apply to a single server, a service can serve trusted internal callers and
+// public callers from different servers.
+//
+// Ex.
+//
+//	internal := grpc.NewServer(grpc.UnaryInterceptor(xgrpc.NewUnaryXErrorInterceptor(xgrpc.ServerOptions{
+//	  AttachEnvelope: true,
+//	})))
+//	public := grpc.NewServer(grpc.UnaryInterceptor(xgrpc.UnaryXErrorInterceptor))
+func NewUnaryXErrorInterceptor(opts ServerOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return unaryXErrorInterceptor(ctx, req, handler, opts)
+	}
+}
+
+func unaryXErrorInterceptor(ctx context.Context, req any, handler grpc.UnaryHandler, opts ServerOptions) (any, error) {
+	// Call the handler with a variable scope, so that the variables it adds to the context are merged into the error
+	ctx = xerror.WithVarScope(ctx)
 	resp, err := handler(ctx, req)
 	if err != nil {
 		var xerr *xerror.Error
 		if !errors.As(err, &xerr) {
 			return resp, err
 		}
+		// Aggregated errors, see xerror.Join, are converted to a single xerror
+		xerr = xerror.From(err)
+		_ = xerr.WithContext(ctx)
+		xerror.ObserveResponded(ctx, xerr)
+		var env *xerrorpb.ErrorEnvelope
+		if opts.AttachEnvelope {
+			env = xerr.Envelope()
+		}
 		if xerr.IsDetailsHidden() {
 			_ = xerr.RemoveSensitiveDetails()
 		}
-		return resp, xerr.Status().Err()
+		if !opts.AttachEnvelope {
+			_ = xerr.RemoveProvenance()
+		}
+		return resp, withEnvelope(xerr.Status(), env).Err()
 	}
 	return resp, err
 }
 
 // ErrorFrom is a convenience function that creates a new xerror from a gRPC error. It is meant to be used by
 // gRPC clients to convert gRPC errors returned by a server to xerrors. If the error isn't a gRPC error, then
-// it returns an xerror with the status code Unknown.
+// it returns an xerror with the status code Unknown. If the error already is an xerror, ex. since it was returned by
+// UnaryClientXErrorInterceptor, then it is returned as is.
+//
+// If the server attached the complete xerror as an envelope detail, see ServerOptions.AttachEnvelope, then the xerror is
+// restored from it, including its log level and runtime state.
 //
 // Ex.
 //
@@ -43,5 +112,58 @@ func ErrorFrom(err error) *xerror.Error {
 	if err == nil {
 		return nil
 	}
-	return new(xerror.Error).SetStatus(status.Convert(err))
+	var xerr *xerror.Error
+	if errors.As(err, &xerr) {
+		return xerr
+	}
+	st, env := envelopeFrom(status.Convert(err))
+	if env != nil {
+		if xerr, err := xerror.FromEnvelope(env); err == nil {
+			return xerr
+		}
+	}
+	return new(xerror.Error).SetStatus(st)
+}
+
+// UnaryClientXErrorInterceptor is a gRPC client unary interceptor that converts the errors returned by the server to
+// xerrors, see ErrorFrom. If enabled with Init, the called method is recorded in the provenance of the error, see
+// Options.RecordProvenance.
+func UnaryClientXErrorInterceptor(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err == nil {
+		return nil
+	}
+	xerr := ErrorFrom(err)
+	if options.RecordProvenance {
+		_ = xerr.AddHop(method)
+	}
+	return xerr
+}
+
+// UnaryClientMappingInterceptor returns a gRPC client unary interceptor that re-exposes the errors returned by the
+// server as declared by the policy, see xerror.MappingPolicy. The errors are converted to xerrors using ErrorFrom before
+// the policy is applied.
+//
+// Since the policy typically depends on the server, the interceptor is meant to be added to the connection to that
+// server. If it's chained with UnaryClientXErrorInterceptor, then it should come first, so that the provenance of the
+// error records the status code returned by the server rather than the mapped one.
+//
+// Ex.
+//
+//	conn, err := grpc.NewClient(target, grpc.WithChainUnaryInterceptor(
+//	  xgrpc.UnaryClientMappingInterceptor(policy),
+//	  xgrpc.UnaryClientXErrorInterceptor,
+//	))
+func UnaryClientMappingInterceptor(policy xerror.MappingPolicy) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			return nil
+		}
+		return policy.Apply(ErrorFrom(err))
+	}
 }
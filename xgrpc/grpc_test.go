@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tobbstr/golden"
 	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xerrorpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -25,7 +27,7 @@ func TestErrorFrom(t *testing.T) {
 		{
 			name: "err is of type status.Status",
 			args: args{err: status.New(codes.Canceled, "request cancelled by the client").Err()},
-			want: xerror.NewCancelled(),
+			want: xerror.NewCancelled().SetLogLevel(xerror.LogLevelUnspecified),
 		},
 		{
 			name: "err is not of type status.Status",
@@ -125,3 +127,288 @@ func TestUnaryXErrorInterceptor(t *testing.T) {
 		})
 	}
 }
+
+func TestUnaryXErrorInterceptor_ObservesErrors(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	var observedCode codes.Code
+	var observedReason string
+	xerror.RegisterObserver(xerror.ObserverFunc(func(ctx context.Context, xerr *xerror.Error) {
+		observedCode = xerr.StatusCode()
+		observedReason = xerr.ErrorInfo().Value.Reason
+	}), xerror.ObserverOptions{})
+	defer xerror.ResetObservers()
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+			HideDetails()
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	_, err := UnaryXErrorInterceptor(context.Background(), nil, nil, handler)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Error(err)
+	require.Equal(codes.Aborted, observedCode)
+	// The observer must see the error before the sensitive details are removed
+	require.Equal("VERSION_MISMATCH", observedReason)
+}
+
+func TestUnaryXErrorInterceptor_MergesContextVars(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	var observed []xerror.Var
+	xerror.RegisterObserver(xerror.ObserverFunc(func(ctx context.Context, xerr *xerror.Error) {
+		observed = xerr.RuntimeState()
+	}), xerror.ObserverOptions{})
+	defer xerror.ResetObservers()
+
+	ctx := xerror.WithVars(context.Background(),
+		xerror.Var{Name: "tenant_id", Value: "tenant-1"},
+		xerror.Var{Name: "user_id", Value: "user-1"},
+	)
+	ctx = xerror.WithVars(ctx,
+		xerror.Var{Name: "operation", Value: "CreateUser"},
+		xerror.Var{Name: "tenant_id", Value: "tenant-2"}, // replaces the value but keeps the position
+	)
+
+	createOrder := func(ctx context.Context) error {
+		_ = xerror.WithVars(ctx, xerror.Var{Name: "order_id", Value: "order-1"})
+		return xerror.NewInternal(errors.New("boom")).
+			AddVar("user_id", "user-2") // takes precedence over the context variable
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		// Variables added by the handler reach the interceptor through the variable scope it installed
+		return nil, createOrder(ctx)
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	_, err := UnaryXErrorInterceptor(ctx, nil, nil, handler)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Error(err)
+	require.Equal([]xerror.Var{
+		{Name: "user_id", Value: "user-2"},
+		{Name: "tenant_id", Value: "tenant-2"},
+		{Name: "operation", Value: "CreateUser"},
+		{Name: "order_id", Value: "order-1"},
+	}, observed)
+}
+
+func TestUnaryXErrorInterceptor_AttachesEnvelope(t *testing.T) {
+	type given struct {
+		interceptor grpc.UnaryServerInterceptor
+	}
+	type want struct {
+		envelope     bool
+		provenance   bool
+		logLevel     xerror.LogLevel
+		runtimeState []xerror.Var
+		reason       string
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name:  "envelope attached",
+			given: given{interceptor: NewUnaryXErrorInterceptor(ServerOptions{AttachEnvelope: true})},
+			want: want{
+				envelope:     true,
+				provenance:   true,
+				logLevel:     xerror.LogLevelWarn,
+				runtimeState: []xerror.Var{{Name: "order_id", Value: "order-1"}},
+				reason:       "VERSION_MISMATCH",
+			},
+		},
+		{
+			name:  "envelope not attached",
+			given: given{interceptor: NewUnaryXErrorInterceptor(ServerOptions{})},
+			want:  want{logLevel: xerror.LogLevelUnspecified},
+		},
+		{
+			name:  "default interceptor never attaches envelope",
+			given: given{interceptor: UnaryXErrorInterceptor},
+			want:  want{logLevel: xerror.LogLevelUnspecified},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			handler := func(ctx context.Context, req any) (any, error) {
+				return nil, xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+					AddVar("order_id", "order-1").
+					AddHop("/stock.v1.StockService/Reserve").
+					HideDetails()
+			}
+
+			/* ---------------------------------- When ---------------------------------- */
+			_, err := tt.given.interceptor(context.Background(), nil, nil, handler)
+			got := ErrorFrom(err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(codes.Aborted, got.StatusCode())
+			require.Equal(tt.want.logLevel, got.LogLevel())
+			require.Equal(tt.want.runtimeState, got.RuntimeState())
+			require.Equal(tt.want.reason, got.ErrorInfo().Value.Reason)
+			// The status itself never carries the hidden details
+			st := status.Convert(err)
+			var hasEnvelope bool
+			for _, detail := range st.Details() {
+				_, isErrorInfo := detail.(*errdetails.ErrorInfo)
+				require.False(isErrorInfo)
+				if _, ok := detail.(*xerrorpb.ErrorEnvelope); ok {
+					hasEnvelope = true
+				}
+			}
+			require.Equal(tt.want.envelope, hasEnvelope)
+			require.Equal(tt.want.provenance, got.Provenance().Valid)
+		})
+	}
+}
+
+func TestUnaryXErrorInterceptor_RemovesProvenance(t *testing.T) {
+	tests := []struct {
+		name        string
+		interceptor grpc.UnaryServerInterceptor
+		want        bool
+	}{
+		{
+			name:        "default interceptor",
+			interceptor: UnaryXErrorInterceptor,
+			want:        false,
+		},
+		{
+			name:        "interceptor without envelope",
+			interceptor: NewUnaryXErrorInterceptor(ServerOptions{}),
+			want:        false,
+		},
+		{
+			name:        "interceptor with envelope",
+			interceptor: NewUnaryXErrorInterceptor(ServerOptions{AttachEnvelope: true}),
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			xerror.Init("checkout.example.com")
+			handler := func(ctx context.Context, req any) (any, error) {
+				return nil, xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "order", ResourceName: "orders/1"}).
+					AddHop("/order.v1.OrderService/GetOrder")
+			}
+
+			/* ---------------------------------- When ---------------------------------- */
+			_, err := tt.interceptor(context.Background(), nil, nil, handler)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			var hasProvenance bool
+			for _, detail := range status.Convert(err).Details() {
+				if _, ok := detail.(*xerrorpb.Provenance); ok {
+					hasProvenance = true
+				}
+			}
+			require.Equal(tt.want, hasProvenance)
+		})
+	}
+}
+
+func TestUnaryClientXErrorInterceptor(t *testing.T) {
+	type want struct {
+		code       codes.Code
+		provenance xerror.Optional[xerror.Provenance]
+	}
+	tests := []struct {
+		name             string
+		recordProvenance bool
+		err              error
+		want             want
+	}{
+		{
+			name:             "provenance recorded",
+			recordProvenance: true,
+			err:              status.Error(codes.NotFound, "order not found"),
+			want: want{
+				code: codes.NotFound,
+				provenance: xerror.Optional[xerror.Provenance]{Valid: true, Value: xerror.Provenance{Hops: []xerror.Hop{
+					{Domain: "checkout.example.com", Method: "/order.v1.OrderService/GetOrder", Code: codes.NotFound},
+				}}},
+			},
+		},
+		{
+			name: "provenance not recorded",
+			err:  status.Error(codes.NotFound, "order not found"),
+			want: want{code: codes.NotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			xerror.Init("checkout.example.com")
+			Init(Options{RecordProvenance: tt.recordProvenance})
+			defer Init(Options{})
+
+			invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				return tt.err
+			}
+
+			/* ---------------------------------- When ---------------------------------- */
+			err := UnaryClientXErrorInterceptor(context.Background(), "/order.v1.OrderService/GetOrder", nil, nil, nil, invoker)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			got := ErrorFrom(err)
+			require.Equal(t, tt.want.code, got.StatusCode())
+			require.Equal(t, tt.want.provenance, got.Provenance())
+		})
+	}
+}
+
+func TestUnaryClientMappingInterceptor(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	xerror.Init("checkout.example.com")
+	Init(Options{RecordProvenance: true})
+	defer Init(Options{})
+
+	policy := xerror.MappingPolicy{Rules: []xerror.MappingRule{
+		{Code: codes.NotFound, Action: xerror.MappingActionConvert, ConvertTo: codes.FailedPrecondition},
+	}}
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.NotFound, "order not found")
+	}
+	// Chained as with grpc.WithChainUnaryInterceptor(UnaryClientMappingInterceptor(policy), UnaryClientXErrorInterceptor)
+	chained := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return UnaryClientXErrorInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	err := UnaryClientMappingInterceptor(policy)(context.Background(), "/order.v1.OrderService/GetOrder", nil, nil, nil, chained)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	got := ErrorFrom(err)
+	require.Equal(t, codes.FailedPrecondition, got.StatusCode())
+	require.Equal(t, "order not found", got.StatusMessage())
+	require.Equal(t, []xerror.Hop{
+		{Domain: "checkout.example.com", Method: "/order.v1.OrderService/GetOrder", Code: codes.NotFound},
+	}, got.Provenance().Value.Hops)
+}
+
+func TestUnaryXErrorInterceptor_SentinelIsNotModified(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	errOutOfStock := xerror.NewSentinel(codes.ResourceExhausted, "order.example.com", "OUT_OF_STOCK")
+	ctx := xerror.WithVars(context.Background(), xerror.Var{Name: "tenant_id", Value: "tenant-1"})
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, errOutOfStock
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	_, err := UnaryXErrorInterceptor(ctx, nil, nil, handler)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal(codes.ResourceExhausted, status.Code(err))
+	require.Empty(errOutOfStock.RuntimeState())
+	require.Len(errOutOfStock.StatusProto().GetDetails(), 1)
+}
@@ -0,0 +1,248 @@
+package xhttp
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tobbstr/xerror"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// maxErrorBodySize is the maximum number of bytes read from the body of an error response.
+const maxErrorBodySize = 1 << 20
+
+// Options controls how xerrors are converted from HTTP responses.
+type Options struct {
+	// RecordProvenance makes ErrorFromResponse record the request as a hop in the provenance of the errors it returns,
+	// see xerror.Error.AddHop. The hop consists of the domain of this service, see xerror.Init, the request method and
+	// path, ex. "POST /v1/orders", and the status code.
+	RecordProvenance bool
+}
+
+var options Options
+
+// Init configures how xerrors are converted from HTTP responses.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func Init(opts Options) {
+	options = opts
+}
+
+// ErrorFromResponse is a convenience function that creates a new xerror from a failed HTTP response. It is meant to be
+// used by HTTP clients to convert error responses returned by a server, that responds with RespondFailed or
+// RespondFailedNegotiated, to xerrors. If the response is successful, then it returns nil.
+//
+// The body is parsed according to its content type, see RespondFailedNegotiated for the supported formats, except for
+// plain text. If the body can't be parsed, then the xerror is created from the HTTP status code instead, see
+// ErrorFromStatus. The body is read but not closed.
+//
+// Ex.
+//
+//	resp, err := http.DefaultClient.Do(req)
+//	if err != nil {
+//	  return xerror.From(err)
+//	}
+//	defer resp.Body.Close()
+//	if xerr := xhttp.ErrorFromResponse(resp); xerr != nil {
+//	  return xerr.AddVar("order_id", orderID)
+//	}
+func ErrorFromResponse(resp *http.Response) *xerror.Error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	xerr := errorFromResponse(resp)
+	if options.RecordProvenance && resp.Request != nil {
+		_ = xerr.AddHop(resp.Request.Method + " " + resp.Request.URL.Path)
+	}
+	return xerr
+}
+
+// ErrorFromResponseMapped creates a new xerror from a failed HTTP response, just like ErrorFromResponse, and
+// re-exposes it as declared by the policy, see xerror.MappingPolicy. If the response is successful, then it returns nil.
+func ErrorFromResponseMapped(resp *http.Response, policy xerror.MappingPolicy) *xerror.Error {
+	xerr := ErrorFromResponse(resp)
+	if xerr == nil {
+		return nil
+	}
+	return policy.Apply(xerr)
+}
+
+func errorFromResponse(resp *http.Response) *xerror.Error {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil {
+		if st, ok := statusFromBody(resp.Header.Get("Content-Type"), body); ok {
+			return new(xerror.Error).SetStatus(status.FromProto(st))
+		}
+	}
+	return errorFromStatus(resp.StatusCode, resp.Header, body)
+}
+
+// codesFromHTTPStatus maps HTTP status codes to status codes. It's the inverse of the mapping of the status code
+// registry, see xerror.CodeInfo.HTTPStatus, where the HTTP status codes that more than one status code is mapped to are
+// resolved to the least specific one. It also maps a few HTTP status codes that no status code is mapped to.
+var codesFromHTTPStatus = func() map[int]codes.Code {
+	m := map[int]codes.Code{
+		// Shared with FailedPrecondition and OutOfRange, which both are more specific
+		http.StatusBadRequest: codes.InvalidArgument,
+		// Shared with AlreadyExists, which can't be told apart from other conflicts without the response body
+		http.StatusConflict: codes.Aborted,
+		// Shared with Unknown and DataLoss
+		http.StatusInternalServerError: codes.Internal,
+
+		http.StatusMethodNotAllowed:             codes.Unimplemented,
+		http.StatusRequestTimeout:               codes.DeadlineExceeded,
+		http.StatusGone:                         codes.NotFound,
+		http.StatusPreconditionFailed:           codes.FailedPrecondition,
+		http.StatusRequestEntityTooLarge:        codes.InvalidArgument,
+		http.StatusUnsupportedMediaType:         codes.InvalidArgument,
+		http.StatusRequestedRangeNotSatisfiable: codes.OutOfRange,
+		http.StatusUnprocessableEntity:          codes.InvalidArgument,
+		http.StatusBadGateway:                   codes.Unavailable,
+	}
+	for _, info := range xerror.CodeInfos() {
+		if _, ok := m[info.HTTPStatus]; !ok && info.Code != codes.OK {
+			m[info.HTTPStatus] = info.Code
+		}
+	}
+	return m
+}()
+
+// ErrorFromStatus is a convenience function that creates a new xerror from the status code, headers and body of a
+// failed HTTP response. It is meant to be used when wrapping third-party APIs, whose error responses aren't in any of
+// the formats supported by ErrorFromResponse. If the status code is successful, then it returns nil. The headers may
+// be nil.
+//
+// The status code is derived from the HTTP status code, as the inverse of xerror.CodeInfo.HTTPStatus. Since several
+// status codes are mapped to the same HTTP status code, the least specific one is chosen:
+//   - 400 Bad Request is converted to InvalidArgument, not FailedPrecondition or OutOfRange.
+//   - 409 Conflict is converted to Aborted, not AlreadyExists, since it can't be told if the conflict is a duplicate.
+//   - 500 Internal Server Error is converted to Internal, not Unknown or DataLoss.
+//
+// Other well-known HTTP status codes are converted to their closest status codes, ex. 502 Bad Gateway to Unavailable
+// and 412 Precondition Failed to FailedPrecondition. Other 5xx status codes are converted to Internal, and anything
+// else, including other 4xx status codes such as 418 I'm a teapot, to Unknown.
+//
+// The message and log level are the ones of the status code, see xerror.CodeInfo. If the Retry-After header is set,
+// either as a number of seconds or as an HTTP date, then it's added as a retry info detail. If the body isn't empty,
+// then it's added as a debug info detail, and the error is marked as having hidden details so that the body of the
+// third-party API isn't returned to the callers of this service.
+//
+// Ex.
+//
+//	body, err := io.ReadAll(resp.Body)
+//	if err != nil {
+//	  return xerror.From(err)
+//	}
+//	if xerr := xhttp.ErrorFromStatus(resp.StatusCode, resp.Header, body); xerr != nil {
+//	  return xerr.AddVar("order_id", orderID)
+//	}
+func ErrorFromStatus(statusCode int, header http.Header, body []byte) *xerror.Error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+	return errorFromStatus(statusCode, header, body)
+}
+
+func errorFromStatus(statusCode int, header http.Header, body []byte) *xerror.Error {
+	code, ok := codesFromHTTPStatus[statusCode]
+	if !ok {
+		// Unmapped 4xx status codes are too diverse to be converted to a more specific status code than Unknown
+		switch {
+		case statusCode >= 500 && statusCode < 600:
+			code = codes.Internal
+		default:
+			code = codes.Unknown
+		}
+	}
+
+	info := xerror.CodeInfoOf(code)
+	xerr := new(xerror.Error).SetStatus(status.New(code, info.DefaultMessage)).SetLogLevel(info.LogLevel)
+	if delay, ok := retryAfter(header.Get("Retry-After"), time.Now()); ok {
+		_ = xerr.SetRetryInfo(delay)
+	}
+	if len(body) > 0 {
+		_ = xerr.SetDebugInfo(string(body), nil).HideDetails()
+	}
+	return xerr
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date, see
+// https://www.rfc-editor.org/rfc/rfc9110#field.retry-after. It returns false if the value is empty or malformed, or if
+// the date has already passed.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, seconds > 0
+	}
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0, false
+	}
+	return date.Sub(now), true
+}
+
+// statusFromBody parses the body of an error response, returning false if it isn't in any of the supported formats.
+func statusFromBody(contentType string, body []byte) (*spb.Status, bool) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	f, ok := formatFromMediaType(mediaType)
+	if !ok {
+		return nil, false
+	}
+	switch f {
+	case formatProtobuf:
+		st := &spb.Status{}
+		if err := proto.Unmarshal(body, st); err != nil {
+			return nil, false
+		}
+		return st, true
+	case formatProblemJSON:
+		var resp problemDetails
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, false
+		}
+		code, ok := xerror.CodeFromName(resp.Code)
+		if !ok {
+			return nil, false
+		}
+		return &spb.Status{Code: int32(code), Message: resp.Detail, Details: unmarshalDetails(resp.Details)}, true
+	case formatJSON:
+		var resp errorResponse
+		if err := json.Unmarshal(body, &resp); err != nil || resp.Error.Status == "" {
+			return nil, false
+		}
+		code, ok := xerror.CodeFromName(resp.Error.Status)
+		if !ok {
+			return nil, false
+		}
+		return &spb.Status{Code: int32(code), Message: resp.Error.Message, Details: unmarshalDetails(resp.Error.Details)}, true
+	default:
+		return nil, false
+	}
+}
+
+// unmarshalDetails unmarshals the JSON encoded details. Details of unknown types are left out.
+func unmarshalDetails(rawJSONDetails []json.RawMessage) []*anypb.Any {
+	var details []*anypb.Any
+	for _, raw := range rawJSONDetails {
+		detail := &anypb.Any{}
+		if err := protojson.Unmarshal(raw, detail); err != nil {
+			continue
+		}
+		details = append(details, detail)
+	}
+	return details
+}
@@ -0,0 +1,307 @@
+package xhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type given struct {
+		accept           string
+		recordProvenance bool
+		respond          func(w http.ResponseWriter, r *http.Request)
+	}
+	type want struct {
+		code       codes.Code
+		message    string
+		violations []xerror.BadRequestViolation
+		provenance xerror.Optional[xerror.Provenance]
+	}
+	invalidArgument := func(w http.ResponseWriter, r *http.Request) {
+		RespondFailedNegotiated(w, r, xerror.NewInvalidArgument("age", "must be greater than 0"))
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name:  "json",
+			given: given{respond: invalidArgument},
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+			},
+		},
+		{
+			name:  "problem json",
+			given: given{accept: "application/problem+json", respond: invalidArgument},
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+			},
+		},
+		{
+			name:  "protobuf",
+			given: given{accept: "application/x-protobuf", respond: invalidArgument},
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+			},
+		},
+		{
+			name: "unsupported body",
+			given: given{respond: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "bad gateway", http.StatusBadGateway)
+			}},
+			want: want{code: codes.Unavailable, message: "the operation is currently unavailable"},
+		},
+		{
+			name:  "provenance recorded",
+			given: given{recordProvenance: true, respond: invalidArgument},
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+				provenance: xerror.Optional[xerror.Provenance]{Valid: true, Value: xerror.Provenance{Hops: []xerror.Hop{
+					{Domain: "myservice.example.com", Method: "POST /v1/users", Code: codes.InvalidArgument},
+				}}},
+			},
+		},
+		{
+			name: "upstream provenance removed by server",
+			given: given{recordProvenance: true, respond: func(w http.ResponseWriter, r *http.Request) {
+				RespondFailedNegotiated(w, r, xerror.NewInvalidArgument("age", "must be greater than 0").
+					AddHop("/user.v1.UserService/CreateUser"))
+			}},
+			want: want{
+				code:       codes.InvalidArgument,
+				message:    "one request arguments was invalid",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than 0"}},
+				provenance: xerror.Optional[xerror.Provenance]{Valid: true, Value: xerror.Provenance{Hops: []xerror.Hop{
+					{Domain: "myservice.example.com", Method: "POST /v1/users", Code: codes.InvalidArgument},
+				}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			Init(Options{RecordProvenance: tt.given.recordProvenance})
+			defer Init(Options{})
+			server := httptest.NewServer(http.HandlerFunc(tt.given.respond))
+			defer server.Close()
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/users", nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", tt.given.accept)
+			resp, err := server.Client().Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := ErrorFromResponse(resp)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.code, got.StatusCode())
+			require.Equal(tt.want.message, got.StatusMessage())
+			require.Equal(tt.want.violations, got.BadRequestViolations())
+			require.Equal(tt.want.provenance, got.Provenance())
+		})
+	}
+}
+
+func TestErrorFromResponse_Successful(t *testing.T) {
+	require.Nil(t, ErrorFromResponse(&http.Response{StatusCode: http.StatusNoContent}))
+}
+
+func TestErrorFromResponseMapped(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RespondFailed(w, xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"}))
+	}))
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	policy := xerror.MappingPolicy{Rules: []xerror.MappingRule{
+		{Code: codes.NotFound, Action: xerror.MappingActionConvert, ConvertTo: codes.FailedPrecondition},
+	}}
+
+	/* ---------------------------------- When ---------------------------------- */
+	got := ErrorFromResponseMapped(resp, policy)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require.Equal(t, codes.FailedPrecondition, got.StatusCode())
+	require.Equal(t, []xerror.ResourceInfo{{ResourceType: "user", ResourceName: "users/1"}}, got.ResourceInfos())
+}
+
+func TestErrorFromStatus(t *testing.T) {
+	type given struct {
+		statusCode int
+		header     http.Header
+		body       []byte
+	}
+	type want struct {
+		code          codes.Code
+		message       string
+		retryInfo     xerror.Optional[xerror.RetryInfo]
+		debugInfo     xerror.Optional[xerror.DebugInfo]
+		detailsHidden bool
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name:  "not found",
+			given: given{statusCode: http.StatusNotFound},
+			want:  want{code: codes.NotFound, message: "requested resource not found"},
+		},
+		{
+			name:  "bad request is converted to invalid argument",
+			given: given{statusCode: http.StatusBadRequest},
+			want:  want{code: codes.InvalidArgument, message: "one or more request arguments were invalid"},
+		},
+		{
+			name:  "conflict is converted to aborted",
+			given: given{statusCode: http.StatusConflict},
+			want:  want{code: codes.Aborted, message: "the operation was aborted"},
+		},
+		{
+			name:  "internal server error is converted to internal",
+			given: given{statusCode: http.StatusInternalServerError},
+			want:  want{code: codes.Internal, message: "an internal server error happened"},
+		},
+		{
+			name:  "unmapped client error",
+			given: given{statusCode: http.StatusTeapot},
+			want:  want{code: codes.Unknown, message: "something unknown happened"},
+		},
+		{
+			name:  "unmapped upgrade required",
+			given: given{statusCode: http.StatusUpgradeRequired},
+			want:  want{code: codes.Unknown, message: "something unknown happened"},
+		},
+		{
+			name:  "unmapped unavailable for legal reasons",
+			given: given{statusCode: http.StatusUnavailableForLegalReasons},
+			want:  want{code: codes.Unknown, message: "something unknown happened"},
+		},
+		{
+			name:  "mapped client error",
+			given: given{statusCode: http.StatusUnprocessableEntity},
+			want:  want{code: codes.InvalidArgument, message: "one or more request arguments were invalid"},
+		},
+		{
+			name:  "unmapped server error",
+			given: given{statusCode: http.StatusHTTPVersionNotSupported},
+			want:  want{code: codes.Internal, message: "an internal server error happened"},
+		},
+		{
+			name:  "redirect",
+			given: given{statusCode: http.StatusFound},
+			want:  want{code: codes.Unknown, message: "something unknown happened"},
+		},
+		{
+			name: "retry after in seconds",
+			given: given{
+				statusCode: http.StatusTooManyRequests,
+				header:     http.Header{"Retry-After": []string{"120"}},
+			},
+			want: want{
+				code:      codes.ResourceExhausted,
+				message:   "the request cannot be completed because the quota has been exhausted",
+				retryInfo: xerror.Optional[xerror.RetryInfo]{Valid: true, Value: xerror.RetryInfo{RetryDelay: 2 * time.Minute}},
+			},
+		},
+		{
+			name: "malformed retry after",
+			given: given{
+				statusCode: http.StatusServiceUnavailable,
+				header:     http.Header{"Retry-After": []string{"soon"}},
+			},
+			want: want{code: codes.Unavailable, message: "the operation is currently unavailable"},
+		},
+		{
+			name:  "body is hidden debug info",
+			given: given{statusCode: http.StatusServiceUnavailable, body: []byte("upstream connect error")},
+			want: want{
+				code:          codes.Unavailable,
+				message:       "the operation is currently unavailable",
+				debugInfo:     xerror.Optional[xerror.DebugInfo]{Valid: true, Value: xerror.DebugInfo{Detail: "upstream connect error"}},
+				detailsHidden: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := ErrorFromStatus(tt.given.statusCode, tt.given.header, tt.given.body)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.code, got.StatusCode())
+			require.Equal(tt.want.message, got.StatusMessage())
+			require.Equal(tt.want.retryInfo, got.RetryInfo())
+			require.Equal(tt.want.debugInfo, got.DebugInfo())
+			require.Equal(tt.want.detailsHidden, got.IsDetailsHidden())
+		})
+	}
+}
+
+func TestErrorFromStatus_Successful(t *testing.T) {
+	require.Nil(t, ErrorFromStatus(http.StatusOK, nil, []byte("{}")))
+}
+
+func TestErrorFromStatus_EveryCode(t *testing.T) {
+	// Every status code that's the only one mapped to its HTTP status code must survive the round trip
+	mapped := map[int]int{}
+	for _, info := range xerror.CodeInfos() {
+		mapped[info.HTTPStatus]++
+	}
+	for _, info := range xerror.CodeInfos() {
+		if info.Code == codes.OK || mapped[info.HTTPStatus] > 1 {
+			continue
+		}
+		require.Equal(t, info.Code, ErrorFromStatus(info.HTTPStatus, nil, nil).StatusCode(), info.Name)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "30", want: 30 * time.Second, wantOK: true},
+		{value: "0", wantOK: false},
+		{value: "-1", wantOK: false},
+		{value: "Fri, 01 Mar 2024 12:01:30 GMT", want: 90 * time.Second, wantOK: true},
+		{value: "Fri, 01 Mar 2024 11:59:00 GMT", wantOK: false},
+		{value: "tomorrow", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := retryAfter(tt.value, now)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
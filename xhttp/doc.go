@@ -0,0 +1,14 @@
+// Package xhttp integrates xerrors with HTTP APIs. Handlers respond with xerrors using RespondFailed or
+// RespondFailedNegotiated, which write their google.rpc.Status in the format accepted by the caller. Clients convert
+// the error responses back to xerrors using ErrorFromResponse, and grpc-gateway servers use GatewayErrorHandler.
+//
+// The responders don't record errors on spans themselves, since this package doesn't depend on OpenTelemetry. The
+// errors passed to RespondFailedNegotiated are passed to the observers registered with xerror.RegisterObserver
+// though, so they are recorded on the server spans by registering the trace observer of the xotel module at
+// startup-time, given that the spans are started by an instrumentation such as otelhttp. RespondFailed has no access
+// to the request's context, so its errors can't be recorded on the spans.
+//
+// Ex.
+//
+//	xerror.RegisterObserver(xotel.NewTraceObserver(xotel.TraceOptions{}), xerror.ObserverOptions{})
+package xhttp
@@ -0,0 +1,57 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xgrpc"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayErrorHandler is a grpc-gateway error handler that responds with the same body and headers as RespondFailed,
+// so that clients can't tell endpoints proxied by the gateway apart from HTTP endpoints using RespondFailed. It's
+// registered using runtime.WithErrorHandler.
+//
+// Errors returned by the gRPC server are converted using xgrpc.ErrorFrom, which means that xerrors attached as
+// envelopes are restored, see xgrpc.ServerOptions.AttachEnvelope, and that their sensitive details are removed if they are
+// marked as hidden. Since the gRPC server has already passed these errors to the observers registered with
+// xerror.RegisterObserver, they aren't observed again. Any other errors, including xerrors returned by servers that are
+// called in-process, see runtime.ServeMux, are responded with just like RespondFailedNegotiated does, except that the
+// response is always JSON.
+//
+// If the error is a runtime.HTTPStatusError wrapping a gRPC error, which the gateway returns for unknown routes and
+// methods, then its HTTP status code is used.
+//
+// Ex.
+//
+//	mux := runtime.NewServeMux(runtime.WithErrorHandler(xhttp.GatewayErrorHandler))
+func GatewayErrorHandler(
+	ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error,
+) {
+	httpStatus := 0
+	var httpStatusErr *runtime.HTTPStatusError
+	if errors.As(err, &httpStatusErr) {
+		httpStatus = httpStatusErr.HTTPStatus
+		err = httpStatusErr.Err
+	}
+
+	if _, ok := status.FromError(err); err == nil || !ok {
+		respondFailed(ctx, w, err, formatJSON)
+		return
+	}
+
+	// The results are assigned, since the error is copied rather than modified if it is a sentinel, see
+	// xerror.NewSentinel
+	xerr := xgrpc.ErrorFrom(err).WithContext(ctx)
+	if xerr.IsDetailsHidden() {
+		xerr = xerr.RemoveSensitiveDetails()
+	}
+	xerr = xerr.RemoveProvenance()
+	if httpStatus == 0 {
+		httpStatus = xerror.CodeInfoOf(xerr.StatusCode()).HTTPStatus
+	}
+	writeError(w, xerr.StatusProto(), xerr.StatusCode(), xerr.StatusMessage(), httpStatus, formatJSON)
+}
@@ -0,0 +1,86 @@
+package xhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xgrpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGatewayErrorHandler(t *testing.T) {
+	xerror.Init("myservice.example.com")
+	newErr := func() *xerror.Error {
+		return xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+			SetDebugInfo("revision 3 != 4", nil).
+			HideDetails()
+	}
+	// serverErr returns the error as returned by a gRPC server using xgrpc.NewUnaryXErrorInterceptor
+	serverErr := func(attachEnvelope bool) error {
+		interceptor := xgrpc.NewUnaryXErrorInterceptor(xgrpc.ServerOptions{AttachEnvelope: attachEnvelope})
+		_, err := interceptor(context.Background(), nil, nil, func(context.Context, any) (any, error) {
+			return nil, newErr()
+		})
+		return err
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "xerror returned in-process",
+			err:  newErr(),
+		},
+		{
+			name: "error returned by gRPC server",
+			err:  serverErr(false),
+		},
+		{
+			name: "error with envelope returned by gRPC server",
+			err:  serverErr(true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			r := httptest.NewRequest(http.MethodGet, "/v1/orders/1", nil)
+			want := httptest.NewRecorder()
+			RespondFailed(want, newErr())
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := httptest.NewRecorder()
+			GatewayErrorHandler(r.Context(), nil, nil, got, r, tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(want.Code, got.Code)
+			require.Equal(want.Header(), got.Header())
+			require.JSONEq(want.Body.String(), got.Body.String())
+		})
+	}
+}
+
+func TestGatewayErrorHandler_HTTPStatusError(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	r := httptest.NewRequest(http.MethodDelete, "/v1/orders", nil)
+	err := &runtime.HTTPStatusError{
+		HTTPStatus: http.StatusMethodNotAllowed,
+		Err:        status.Error(codes.Unimplemented, http.StatusText(http.StatusMethodNotAllowed)),
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	got := httptest.NewRecorder()
+	GatewayErrorHandler(r.Context(), nil, nil, got, r, err)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require.Equal(t, http.StatusMethodNotAllowed, got.Code)
+	require.JSONEq(t, `{"error": {"code": 12, "message": "Method Not Allowed", "status": "UNIMPLEMENTED"}}`, got.Body.String())
+}
@@ -1,15 +1,16 @@
 package xhttp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/tobbstr/xerror"
 	spb "google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // ErrorResponse represents the structure of the error response
@@ -25,52 +26,172 @@ type errorDetails struct {
 	Details []json.RawMessage `json:"details,omitempty"`
 }
 
-// RespondFailed returns a failed response to the client. It expects err to be of type *xerror.Error.
-// If so, the returned error model is the Google Cloud APIs error model as declared in: https://google.aip.dev/193#error-response
+// problemDetails represents the structure of a problem details response as declared in:
+// https://www.rfc-editor.org/rfc/rfc9457
+type problemDetails struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Code is an extension member holding the google.rpc.Code name of the error, ex. "NOT_FOUND".
+	Code string `json:"code"`
+	// Details is an extension member holding the google.rpc.Status details.
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// nonXErrorObserver is called whenever an error that isn't an *xerror.Error is about to be responded with.
+var nonXErrorObserver = func(error) {}
+
+// ObserveNonXErrors registers a function that is called whenever RespondFailed or RespondFailedNegotiated receives an
+// error that isn't an *xerror.Error. Such errors are unexpected and indicate that there's code where the error isn't
+// correctly handled, so the function should typically log or count them.
+//
+// It must only be called at application startup-time. It is NOT thread-safe.
+func ObserveNonXErrors(fn func(err error)) {
+	if fn == nil {
+		fn = func(error) {}
+	}
+	nonXErrorObserver = fn
+}
+
+// RespondFailed returns a failed response to the client. The returned error model is the Google Cloud APIs error model
+// as declared in: https://google.aip.dev/193#error-response
+//
+// If err isn't an *xerror.Error, it is converted using xerror.From, which means that context errors are responded
+// with as Cancelled or DeadlineExceeded errors and any other error is responded with as an Unknown error with hidden
+// details. Before responding, the error is passed to the function registered with ObserveNonXErrors. Aggregated
+// errors, see xerror.Join, are converted to a single xerror in the same way.
 //
-// Otherwise, the response is a generic 500 Internal Server Error.
+// The error is also passed to the observers registered with xerror.RegisterObserver, before any sensitive details are
+// removed. Since there is no request context, the error isn't enriched with the values carried by it, see
+// RespondFailedNegotiated.
 func RespondFailed(w http.ResponseWriter, err error) {
+	respondFailed(context.Background(), w, err, formatJSON)
+}
+
+// RespondFailedNegotiated returns a failed response to the client, just like RespondFailed, but the response format is
+// chosen based on the Accept header of the request. The supported formats are:
+//   - application/json: the Google Cloud APIs error model as declared in: https://google.aip.dev/193#error-response
+//   - application/problem+json: the problem details model as declared in: https://www.rfc-editor.org/rfc/rfc9457
+//   - application/x-protobuf: the binary encoded google.rpc.Status message
+//   - text/plain: a single line of text with the status and the message
+//
+// If the request has no Accept header, or if it accepts any media type, then the response is JSON. If none of the
+// accepted media types are supported, then the response falls back to plain text.
+//
+// Before responding, the error is enriched with the values carried by the request's context, see
+// xerror.Error.WithContext.
+func RespondFailedNegotiated(w http.ResponseWriter, r *http.Request, err error) {
+	respondFailed(r.Context(), w, err, negotiateFormat(r.Header.Get("Accept")))
+}
+
+// VarScope is an HTTP middleware that installs a variable scope in the request's context, see xerror.WithVarScope, so
+// that the variables added with xerror.WithVars anywhere in the handler are merged into the errors responded with by
+// RespondFailedNegotiated.
+//
+// Ex.
+//
+//	http.ListenAndServe(":8080", xhttp.VarScope(mux))
+func VarScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(xerror.WithVarScope(r.Context())))
+	})
+}
+
+func respondFailed(ctx context.Context, w http.ResponseWriter, err error, f format) {
+	if err == nil {
+		err = errors.New("nil error received")
+	}
 	var xerr *xerror.Error
 	if !errors.As(err, &xerr) {
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("non-xerror received"))
-		return
+		nonXErrorObserver(err)
 	}
+	xerr = xerror.From(err)
+	_ = xerr.WithContext(ctx)
+	xerror.ObserveResponded(ctx, xerr)
 
 	if xerr.IsDetailsHidden() {
 		_ = xerr.RemoveSensitiveDetails()
 	}
+	_ = xerr.RemoveProvenance()
 
-	writeError(w, xerr.StatusProto(), xerr.StatusCode(), xerr.StatusMessage())
+	writeError(w, xerr.StatusProto(), xerr.StatusCode(), xerr.StatusMessage(), xerror.CodeInfoOf(xerr.StatusCode()).HTTPStatus, f)
 }
 
-func writeError(w http.ResponseWriter, st *spb.Status, code codes.Code, message string) {
-	rawJSONDetails := make([]json.RawMessage, len(st.Details))
-	for i, detail := range st.Details {
-		b, err := protojson.Marshal(detail)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			_, _ = w.Write([]byte("proto marshalling detail"))
-			return
-		}
-		rawJSONDetails[i] = b
+func writeError(w http.ResponseWriter, st *spb.Status, code codes.Code, message string, httpStatus int, f format) {
+	var (
+		b   []byte
+		err error
+	)
+	switch f {
+	case formatProblemJSON:
+		b, err = marshalProblemDetails(st, code, message, httpStatus)
+	case formatProtobuf:
+		b, err = proto.Marshal(st)
+	case formatText:
+		b = []byte(xerror.CodeInfoOf(code).Name + ": " + message + "\n")
+	default:
+		b, err = marshalErrorResponse(st, code, message)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to marshal error"))
+		return
+	}
+	w.Header().Set("Content-Type", f.contentType())
+	w.WriteHeader(httpStatus)
+	_, _ = w.Write(b)
+}
+
+func marshalErrorResponse(st *spb.Status, code codes.Code, message string) ([]byte, error) {
+	rawJSONDetails, err := marshalDetails(st)
+	if err != nil {
+		return nil, err
 	}
 
 	resp := errorResponse{
 		Error: errorDetails{
 			Code:    int(code),
 			Message: message,
-			Status:  upperSnakeCaseFrom(code.String()),
+			Status:  xerror.CodeInfoOf(code).Name,
 			Details: rawJSONDetails,
 		},
 	}
+	return json.Marshal(&resp)
+}
 
-	b, err := json.Marshal(&resp)
+func marshalProblemDetails(st *spb.Status, code codes.Code, message string, httpStatus int) ([]byte, error) {
+	rawJSONDetails, err := marshalDetails(st)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte("failed to marshal error"))
-		return
+		return nil, err
 	}
-	w.WriteHeader(runtime.HTTPStatusFromCode(code))
-	_, _ = w.Write(b)
+
+	title := http.StatusText(httpStatus)
+	if title == "" {
+		title = xerror.CodeInfoOf(code).Name
+	}
+	resp := problemDetails{
+		Type:    "about:blank",
+		Title:   title,
+		Status:  httpStatus,
+		Detail:  message,
+		Code:    xerror.CodeInfoOf(code).Name,
+		Details: rawJSONDetails,
+	}
+	return json.Marshal(&resp)
+}
+
+func marshalDetails(st *spb.Status) ([]json.RawMessage, error) {
+	if len(st.Details) == 0 {
+		return nil, nil
+	}
+	rawJSONDetails := make([]json.RawMessage, len(st.Details))
+	for i, detail := range st.Details {
+		b, err := protojson.Marshal(detail)
+		if err != nil {
+			return nil, err
+		}
+		rawJSONDetails[i] = b
+	}
+	return rawJSONDetails, nil
 }
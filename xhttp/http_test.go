@@ -1,8 +1,10 @@
 package xhttp
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +13,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tobbstr/golden"
 	"github.com/tobbstr/xerror"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestRespondFailed(t *testing.T) {
@@ -24,8 +29,9 @@ func TestRespondFailed(t *testing.T) {
 		err error
 	}
 	type want struct {
-		code int
-		body string
+		code            int
+		body            string
+		nonXErrorCalled bool
 	}
 	tests := []struct {
 		name  string
@@ -296,12 +302,59 @@ func TestRespondFailed(t *testing.T) {
 				body: "testdata/respond_failed/hide_details.json",
 			},
 		},
+		{
+			name: "wrapped xerror",
+			given: given{
+				err: xerror.Wrap(xerror.NewNotImplemented(), "more context"),
+			},
+			want: want{
+				code: http.StatusNotImplemented,
+				body: "testdata/respond_failed/not_implemented.json",
+			},
+		},
+		{
+			name: "non-xerror",
+			given: given{
+				err: errors.New("something unexpected happened"),
+			},
+			want: want{
+				code:            http.StatusInternalServerError,
+				body:            "testdata/respond_failed/non_xerror.json",
+				nonXErrorCalled: true,
+			},
+		},
+		{
+			name: "context cancelled",
+			given: given{
+				err: fmt.Errorf("calling the database: %w", context.Canceled),
+			},
+			want: want{
+				code:            499,
+				body:            "testdata/respond_failed/context_cancelled.json",
+				nonXErrorCalled: true,
+			},
+		},
+		{
+			name: "context deadline exceeded",
+			given: given{
+				err: fmt.Errorf("calling the database: %w", context.DeadlineExceeded),
+			},
+			want: want{
+				code:            http.StatusGatewayTimeout,
+				body:            "testdata/respond_failed/context_deadline_exceeded.json",
+				nonXErrorCalled: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			/* ---------------------------------- Given --------------------------------- */
 			respRecorder := httptest.NewRecorder()
 
+			var nonXErrorCalled bool
+			ObserveNonXErrors(func(err error) { nonXErrorCalled = true })
+			defer ObserveNonXErrors(nil)
+
 			args := args{w: respRecorder, err: tt.given.err}
 
 			/* ---------------------------------- When ---------------------------------- */
@@ -313,10 +366,174 @@ func TestRespondFailed(t *testing.T) {
 			// Assert the response
 			require := require.New(t)
 			require.Equal(tt.want.code, res.StatusCode)
+			require.Equal("application/json", res.Header.Get("Content-Type"))
 			body := readBody(t, res.Body)
 			var got map[string]any
 			require.NoError(json.Unmarshal(body, &got))
 			golden.JSON(t, tt.want.body, got)
+
+			// Assert the observer
+			require.Equal(tt.want.nonXErrorCalled, nonXErrorCalled)
+		})
+	}
+}
+
+func TestRespondFailedNegotiated(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type args struct {
+		w   http.ResponseWriter
+		r   *http.Request
+		err error
+	}
+	type given struct {
+		accept string
+		err    error
+	}
+	type want struct {
+		code        int
+		contentType string
+		body        string
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name: "no accept header",
+			given: given{
+				err: xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "application/json",
+				body:        "testdata/respond_failed_negotiated/json.json",
+			},
+		},
+		{
+			name: "accepts anything",
+			given: given{
+				accept: "*/*",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "application/json",
+				body:        "testdata/respond_failed_negotiated/json.json",
+			},
+		},
+		{
+			name: "accepts problem json",
+			given: given{
+				accept: "application/problem+json",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "application/problem+json",
+				body:        "testdata/respond_failed_negotiated/problem_json.json",
+			},
+		},
+		{
+			name: "accepts problem json with hidden details",
+			given: given{
+				accept: "application/problem+json",
+				err: xerror.NewDeadlineExceeded().
+					SetDebugInfo("this is a debug message", []string{"line 1", "line 2"}).
+					HideDetails(),
+			},
+			want: want{
+				code:        http.StatusGatewayTimeout,
+				contentType: "application/problem+json",
+				body:        "testdata/respond_failed_negotiated/problem_json_hidden_details.json",
+			},
+		},
+		{
+			name: "accepts protobuf",
+			given: given{
+				accept: "application/x-protobuf",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "application/x-protobuf",
+				body:        "testdata/respond_failed_negotiated/protobuf.json",
+			},
+		},
+		{
+			name: "accepts plain text",
+			given: given{
+				accept: "text/plain",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "text/plain; charset=utf-8",
+				body:        "testdata/respond_failed_negotiated/text.json",
+			},
+		},
+		{
+			name: "picks the media type with the highest quality",
+			given: given{
+				accept: "application/json;q=0.5, application/problem+json;q=0.9, text/plain;q=0.1",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "application/problem+json",
+				body:        "testdata/respond_failed_negotiated/problem_json.json",
+			},
+		},
+		{
+			name: "falls back to plain text",
+			given: given{
+				accept: "text/html, application/xml",
+				err:    xerror.NewInvalidArgument("age", "must be greater than 0"),
+			},
+			want: want{
+				code:        http.StatusBadRequest,
+				contentType: "text/plain; charset=utf-8",
+				body:        "testdata/respond_failed_negotiated/text.json",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			respRecorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.given.accept != "" {
+				req.Header.Set("Accept", tt.given.accept)
+			}
+
+			args := args{w: respRecorder, r: req, err: tt.given.err}
+
+			/* ---------------------------------- When ---------------------------------- */
+			RespondFailedNegotiated(args.w, args.r, args.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			res := respRecorder.Result()
+
+			// Assert the response
+			require := require.New(t)
+			require.Equal(tt.want.code, res.StatusCode)
+			require.Equal(tt.want.contentType, res.Header.Get("Content-Type"))
+			body := readBody(t, res.Body)
+			var got any
+			switch tt.want.contentType {
+			case "application/x-protobuf":
+				var st spb.Status
+				require.NoError(proto.Unmarshal(body, &st))
+				b, err := protojson.Marshal(&st)
+				require.NoError(err)
+				require.NoError(json.Unmarshal(b, &got))
+			case "text/plain; charset=utf-8":
+				got = string(body)
+			default:
+				require.NoError(json.Unmarshal(body, &got))
+			}
+			golden.JSON(t, tt.want.body, got)
 		})
 	}
 }
@@ -0,0 +1,90 @@
+package xhttp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// format is the media type used to encode an error response.
+type format uint8
+
+const (
+	formatJSON format = iota
+	formatProblemJSON
+	formatProtobuf
+	formatText
+)
+
+func (f format) contentType() string {
+	switch f {
+	case formatProblemJSON:
+		return "application/problem+json"
+	case formatProtobuf:
+		return "application/x-protobuf"
+	case formatText:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// formatFromMediaType returns the format for the given media range and true if it is supported, otherwise it
+// returns false.
+func formatFromMediaType(mediaType string) (format, bool) {
+	switch mediaType {
+	case "*/*", "application/*", "application/json":
+		return formatJSON, true
+	case "application/problem+json":
+		return formatProblemJSON, true
+	case "application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf":
+		return formatProtobuf, true
+	case "text/*", "text/plain":
+		return formatText, true
+	default:
+		return 0, false
+	}
+}
+
+// negotiateFormat returns the format that best matches the Accept header value. Media ranges are ranked by their
+// quality value, and in case of a tie, the first one listed wins.
+//
+// If the header is empty, then JSON is returned. If none of the listed media ranges are supported, then plain text is
+// returned.
+func negotiateFormat(accept string) format {
+	if strings.TrimSpace(accept) == "" {
+		return formatJSON
+	}
+
+	best, bestQ, found := formatText, 0.0, false
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		f, ok := formatFromMediaType(strings.ToLower(strings.TrimSpace(mediaType)))
+		if !ok {
+			continue
+		}
+		q := qualityFrom(params)
+		if q <= 0 {
+			continue
+		}
+		if !found || q > bestQ {
+			best, bestQ, found = f, q, true
+		}
+	}
+	return best
+}
+
+// qualityFrom returns the "q" parameter value of a media range's parameters. If there is none, it returns 1.
+func qualityFrom(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return q
+	}
+	return 1
+}
@@ -0,0 +1,162 @@
+/*
+Package xjsonrpc maps xerrors to and from JSON-RPC 2.0 error objects, see https://www.jsonrpc.org/specification#error_object.
+*/
+package xjsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tobbstr/xerror"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// The error codes that are pre-defined by the JSON-RPC 2.0 specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// codeServerErrorBase is the base of the codes that status codes without a pre-defined counterpart are mapped to. The
+// resulting codes, -32001 to -32016, are in the range reserved for implementation-defined server errors.
+const codeServerErrorBase = -32000
+
+// ErrorObject is a JSON-RPC 2.0 error object.
+type ErrorObject struct {
+	// Code is the JSON-RPC error code, see CodeFrom.
+	Code int `json:"code"`
+	// Message is the status message.
+	Message string `json:"message"`
+	// Data holds the status code name and the status details.
+	Data *ErrorData `json:"data,omitempty"`
+}
+
+// Error implements the error interface.
+func (obj *ErrorObject) Error() string {
+	return fmt.Sprintf("jsonrpc error: code = %d message = %s", obj.Code, obj.Message)
+}
+
+// ErrorData is the data member of the error objects created by ErrorObjectFrom.
+type ErrorData struct {
+	// Status is the status code name in upper snake case, ex. "NOT_FOUND".
+	Status string `json:"status"`
+	// Details are the status details in the protobuf JSON format.
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+// CodeFrom returns the JSON-RPC error code of the status code. InvalidArgument, Unimplemented and Internal, Unknown and
+// DataLoss are mapped to the pre-defined error codes for invalid params, method not found and internal error. The other
+// status codes are mapped to implementation-defined server error codes, i.e. -32000 minus the status code.
+func CodeFrom(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return CodeInvalidParams
+	case codes.Unimplemented:
+		return CodeMethodNotFound
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		return CodeInternalError
+	default:
+		return codeServerErrorBase - int(code)
+	}
+}
+
+// ErrorObjectFrom converts the error to a JSON-RPC error object that's returned to a caller. The status code name and
+// details are kept in the data member, see ErrorData.
+//
+// Just like xhttp.RespondFailedNegotiated, the error is converted using xerror.From if it isn't an xerror, it's
+// enriched with the values carried by ctx (see xerror.Error.WithContext), and it's passed to the observers registered
+// with xerror.RegisterObserver. Sensitive details are removed if the error is marked as hidden.
+func ErrorObjectFrom(ctx context.Context, err error) *ErrorObject {
+	xerr := xerror.From(err)
+	if xerr == nil {
+		xerr = xerror.From(errors.New("nil error received"))
+	}
+	_ = xerr.WithContext(ctx)
+	xerror.ObserveResponded(ctx, xerr)
+	if xerr.IsDetailsHidden() {
+		_ = xerr.RemoveSensitiveDetails()
+	}
+	_ = xerr.RemoveProvenance()
+
+	obj := &ErrorObject{
+		Code:    CodeFrom(xerr.StatusCode()),
+		Message: xerr.StatusMessage(),
+		Data:    &ErrorData{Status: xerror.CodeInfoOf(xerr.StatusCode()).Name},
+	}
+	for _, detail := range xerr.StatusProto().GetDetails() {
+		b, err := protojson.Marshal(detail)
+		if err != nil {
+			continue
+		}
+		obj.Data.Details = append(obj.Data.Details, b)
+	}
+	return obj
+}
+
+// ErrorFrom is a convenience function that creates a new xerror from an error returned by a JSON-RPC client, i.e. an
+// error wrapping an *ErrorObject. If the error object has a data member as created by ErrorObjectFrom, then the status
+// code and details are restored from it. Otherwise, the status code is derived from the JSON-RPC error code, see
+// CodeFrom, where parse errors and invalid requests are converted to InvalidArgument and unknown codes to Unknown. If
+// the error doesn't wrap an error object, then it returns an xerror with the status code Unknown. If the error already
+// is an xerror, then it is returned as is.
+//
+// Ex.
+//
+//	if resp.Error != nil {
+//	  return xjsonrpc.ErrorFrom(resp.Error).AddVar("method", "order.create")
+//	}
+func ErrorFrom(err error) *xerror.Error {
+	if err == nil {
+		return nil
+	}
+	var xerr *xerror.Error
+	if errors.As(err, &xerr) {
+		return xerr
+	}
+	var obj *ErrorObject
+	if !errors.As(err, &obj) {
+		return new(xerror.Error).SetStatus(status.New(codes.Unknown, err.Error()))
+	}
+	if obj == nil {
+		return nil
+	}
+
+	st := &spb.Status{Code: int32(statusCodeFrom(obj.Code)), Message: obj.Message}
+	if obj.Data != nil {
+		if code, ok := xerror.CodeFromName(obj.Data.Status); ok {
+			st.Code = int32(code)
+		}
+		for _, raw := range obj.Data.Details {
+			detail := &anypb.Any{}
+			// Details of unknown types are left out
+			if err := protojson.Unmarshal(raw, detail); err == nil {
+				st.Details = append(st.Details, detail)
+			}
+		}
+	}
+	return new(xerror.Error).SetStatus(status.FromProto(st))
+}
+
+func statusCodeFrom(code int) codes.Code {
+	switch code {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		return codes.InvalidArgument
+	case CodeMethodNotFound:
+		return codes.Unimplemented
+	case CodeInternalError:
+		return codes.Internal
+	}
+	if c := codes.Code(codeServerErrorBase - code); code < codeServerErrorBase && c <= codes.Unauthenticated {
+		return c
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,151 @@
+package xjsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+func TestErrorObjectFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "invalid argument",
+			err:  xerror.NewInvalidArgument("age", "must be greater than 0"),
+			want: `{
+				"code": -32602,
+				"message": "one request arguments was invalid",
+				"data": {
+					"status": "INVALID_ARGUMENT",
+					"details": [{
+						"@type": "type.googleapis.com/google.rpc.BadRequest",
+						"fieldViolations": [{"field": "age", "description": "must be greater than 0"}]
+					}]
+				}
+			}`,
+		},
+		{
+			name: "not found",
+			err:  xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"}),
+			want: `{
+				"code": -32005,
+				"message": "requested resource not found",
+				"data": {
+					"status": "NOT_FOUND",
+					"details": [{
+						"@type": "type.googleapis.com/google.rpc.ResourceInfo",
+						"resourceType": "user",
+						"resourceName": "users/1"
+					}]
+				}
+			}`,
+		},
+		{
+			name: "canceled",
+			err:  context.Canceled,
+			want: `{"code": -32001, "message": "request cancelled by the client", "data": {"status": "CANCELLED"}}`,
+		},
+		{
+			name: "hidden details",
+			err:  xerror.NewInternal(errors.New("boom")).SetDebugInfo("stack", nil),
+			want: `{"code": -32603, "message": "boom", "data": {"status": "INTERNAL"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := ErrorObjectFrom(context.Background(), tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			b, err := json.Marshal(got)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestErrorFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type want struct {
+		code       codes.Code
+		message    string
+		violations []xerror.BadRequestViolation
+	}
+	tests := []struct {
+		name string
+		err  error
+		want want
+	}{
+		{
+			name: "round trip",
+			err:  ErrorObjectFrom(context.Background(), xerror.NewOutOfRange("age", "must be less than 150")),
+			want: want{
+				code:       codes.OutOfRange,
+				message:    "one request argument was out of range",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be less than 150"}},
+			},
+		},
+		{
+			name: "status name",
+			err:  &ErrorObject{Code: 42, Message: "request canceled", Data: &ErrorData{Status: "CANCELLED"}},
+			want: want{code: codes.Canceled, message: "request canceled"},
+		},
+		{
+			name: "pre-defined code without data",
+			err:  &ErrorObject{Code: CodeParseError, Message: "Parse error"},
+			want: want{code: codes.InvalidArgument, message: "Parse error"},
+		},
+		{
+			name: "server error code without data",
+			err:  &ErrorObject{Code: -32014, Message: "try again later"},
+			want: want{code: codes.Unavailable, message: "try again later"},
+		},
+		{
+			name: "application-defined code without data",
+			err:  &ErrorObject{Code: 42, Message: "something happened"},
+			want: want{code: codes.Unknown, message: "something happened"},
+		},
+		{
+			name: "wrapped error object",
+			err:  fmt.Errorf("calling order.create: %w", &ErrorObject{Code: CodeMethodNotFound, Message: "Method not found"}),
+			want: want{code: codes.Unimplemented, message: "Method not found"},
+		},
+		{
+			name: "not an error object",
+			err:  errors.New("connection refused"),
+			want: want{code: codes.Unknown, message: "connection refused"},
+		},
+		{
+			name: "xerror",
+			err:  xerror.NewOutOfRange("age", "must be less than 150"),
+			want: want{
+				code:       codes.OutOfRange,
+				message:    "one request argument was out of range",
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be less than 150"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := ErrorFrom(tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.Equal(t, tt.want.code, got.StatusCode())
+			require.Equal(t, tt.want.message, got.StatusMessage())
+			require.Equal(t, tt.want.violations, got.BadRequestViolations())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Package xmetrics contains the functionality shared by the metric integrations, such as the xprometheus and xotel
+packages. It turns xerrors into metric labels and guards against unbounded label cardinality.
+*/
+package xmetrics
+
+import (
+	"sync"
+
+	"github.com/tobbstr/xerror"
+)
+
+// OtherReason is the reason label value used when the real reason would exceed the cardinality limit or when it isn't
+// among the allowed reasons.
+const OtherReason = "OTHER"
+
+// DefaultMaxReasons is the default maximum number of distinct domain and reason pairs that are used as label values.
+const DefaultMaxReasons = 100
+
+// Labels are the label values of an observed error.
+type Labels struct {
+	// Code is the canonical name of the status code, ex. "NOT_FOUND", see xerror.CodeInfo.
+	Code string
+	// Domain is the ErrorInfo domain, or empty if there is no ErrorInfo detail.
+	Domain string
+	// Reason is the ErrorInfo reason, or empty if there is no ErrorInfo detail. It is OtherReason if the cardinality
+	// guard kicked in.
+	Reason string
+	// LogLevel is the name of the error's log level, ex. "warn".
+	LogLevel string
+}
+
+// Options controls the cardinality guard of a Labeler.
+type Options struct {
+	// AllowedReasons is an optional list of domain and reason pairs, as returned by xerror.DomainType(), that may be
+	// used as label values. If it's non-empty, any other reason is replaced by OtherReason.
+	AllowedReasons []string
+	// MaxReasons is the maximum number of distinct domain and reason pairs that are used as label values. Once it's
+	// reached, any previously unseen reason is replaced by OtherReason. If it is zero, DefaultMaxReasons is used. If
+	// it is negative, there is no limit.
+	MaxReasons int
+}
+
+// Labeler turns xerrors into label values. It is safe for concurrent use.
+type Labeler struct {
+	allowed    map[string]struct{}
+	maxReasons int
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewLabeler returns a new Labeler.
+func NewLabeler(opts Options) *Labeler {
+	l := &Labeler{maxReasons: opts.MaxReasons, seen: make(map[string]struct{})}
+	if l.maxReasons == 0 {
+		l.maxReasons = DefaultMaxReasons
+	}
+	if len(opts.AllowedReasons) > 0 {
+		l.allowed = make(map[string]struct{}, len(opts.AllowedReasons))
+		for _, r := range opts.AllowedReasons {
+			l.allowed[r] = struct{}{}
+		}
+	}
+	return l
+}
+
+// LabelsFrom returns the label values of the error.
+func (l *Labeler) LabelsFrom(xerr *xerror.Error) Labels {
+	labels := Labels{Code: xerror.CodeInfoOf(xerr.StatusCode()).Name, LogLevel: xerr.LogLevel().String()}
+	info := xerr.ErrorInfo()
+	if !info.Valid {
+		return labels
+	}
+	labels.Domain = info.Value.Domain
+	labels.Reason = l.guardReason(info.Value.Domain, info.Value.Reason)
+	return labels
+}
+
+func (l *Labeler) guardReason(domain, reason string) string {
+	key := xerror.DomainType(domain, reason)
+	if l.allowed != nil {
+		if _, ok := l.allowed[key]; !ok {
+			return OtherReason
+		}
+		return reason
+	}
+	if l.maxReasons < 0 {
+		return reason
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.seen[key]; ok {
+		return reason
+	}
+	if len(l.seen) >= l.maxReasons {
+		return OtherReason
+	}
+	l.seen[key] = struct{}{}
+	return reason
+}
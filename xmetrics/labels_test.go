@@ -0,0 +1,88 @@
+package xmetrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+)
+
+func TestLabeler_LabelsFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type given struct {
+		opts     Options
+		previous []*xerror.Error
+	}
+	tests := []struct {
+		name  string
+		given given
+		xerr  *xerror.Error
+		want  Labels
+	}{
+		{
+			name: "error without error info",
+			xerr: xerror.NewInternal(errors.New("boom")),
+			want: Labels{Code: "INTERNAL", LogLevel: "error"},
+		},
+		{
+			name: "error with error info",
+			xerr: newAborted("VERSION_MISMATCH"),
+			want: Labels{Code: "ABORTED", Domain: "myservice.example.com", Reason: "VERSION_MISMATCH", LogLevel: "warn"},
+		},
+		{
+			name: "reason is not allowed",
+			given: given{
+				opts: Options{AllowedReasons: []string{xerror.DomainType("myservice.example.com", "OTHER_REASON")}},
+			},
+			xerr: newAborted("VERSION_MISMATCH"),
+			want: Labels{Code: "ABORTED", Domain: "myservice.example.com", Reason: OtherReason, LogLevel: "warn"},
+		},
+		{
+			name: "reason is allowed",
+			given: given{
+				opts: Options{AllowedReasons: []string{xerror.DomainType("myservice.example.com", "VERSION_MISMATCH")}},
+			},
+			xerr: newAborted("VERSION_MISMATCH"),
+			want: Labels{Code: "ABORTED", Domain: "myservice.example.com", Reason: "VERSION_MISMATCH", LogLevel: "warn"},
+		},
+		{
+			name: "max reasons reached",
+			given: given{
+				opts:     Options{MaxReasons: 1},
+				previous: []*xerror.Error{newAborted("FIRST")},
+			},
+			xerr: newAborted("SECOND"),
+			want: Labels{Code: "ABORTED", Domain: "myservice.example.com", Reason: OtherReason, LogLevel: "warn"},
+		},
+		{
+			name: "max reasons reached but reason already seen",
+			given: given{
+				opts:     Options{MaxReasons: 1},
+				previous: []*xerror.Error{newAborted("FIRST")},
+			},
+			xerr: newAborted("FIRST"),
+			want: Labels{Code: "ABORTED", Domain: "myservice.example.com", Reason: "FIRST", LogLevel: "warn"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			l := NewLabeler(tt.given.opts)
+			for _, xerr := range tt.given.previous {
+				_ = l.LabelsFrom(xerr)
+			}
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := l.LabelsFrom(tt.xerr)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newAborted(reason string) *xerror.Error {
+	return xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: reason})
+}
@@ -0,0 +1,137 @@
+/*
+Package xmq helps message queue consumers, ex. for Kafka, NATS or SQS, to decide what to do with a message based on the
+error returned by its handler, and to carry the error in the headers of dead-lettered messages.
+*/
+package xmq
+
+import (
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+// Disposition is what a consumer should do with a message after handling it.
+type Disposition uint8
+
+const (
+	// DispositionAck acknowledges the message, since it was handled or there is nothing more to do with it.
+	DispositionAck Disposition = iota
+	// DispositionRetryNow redelivers the message immediately.
+	DispositionRetryNow
+	// DispositionRetryWithBackoff redelivers the message after a delay, which should grow exponentially with the number
+	// of deliveries.
+	DispositionRetryWithBackoff
+	// DispositionDeadLetter moves the message to a dead-letter queue, since retrying won't help.
+	DispositionDeadLetter
+)
+
+// String returns the name of the disposition, ex. "retry_with_backoff".
+func (d Disposition) String() string {
+	switch d {
+	case DispositionAck:
+		return "ack"
+	case DispositionRetryNow:
+		return "retry_now"
+	case DispositionRetryWithBackoff:
+		return "retry_with_backoff"
+	case DispositionDeadLetter:
+		return "dead_letter"
+	default:
+		return "unknown"
+	}
+}
+
+// Rule matches errors and declares the disposition of the messages whose handler returned them. All of its non-zero
+// matching fields must match for the rule to match.
+type Rule struct {
+	// Code matches errors with the status code. If it's codes.OK, then errors with any status code match.
+	Code codes.Code
+	// Domain matches errors whose error info detail has the domain. If it's empty, then errors with any domain match,
+	// including errors without an error info detail.
+	Domain string
+	// Reason matches errors whose error info detail has the reason. If it's empty, then errors with any reason match.
+	Reason string
+	// Disposition is the disposition of messages whose handler returned a matching error.
+	Disposition Disposition
+}
+
+// Classifier decides the disposition of messages based on the errors returned by their handlers. The rules are
+// evaluated in order, and the first matching rule is applied. If no rule matches, then the disposition is decided as
+// by Classify.
+//
+// Ex.
+//
+//	classifier := xmq.Classifier{
+//	  Rules: []xmq.Rule{
+//	    // The order may not have been replicated yet
+//	    {Code: codes.NotFound, Disposition: xmq.DispositionRetryWithBackoff},
+//	    {Domain: payment.Domain, Reason: payment.ReasonCardDeclined, Disposition: xmq.DispositionAck},
+//	  },
+//	}
+type Classifier struct {
+	// Rules are the rules that are evaluated in order.
+	Rules []Rule
+}
+
+// Classify returns the disposition of the message whose handler returned err. If err isn't an xerror, it's converted
+// using xerror.From.
+func (c Classifier) Classify(err error) Disposition {
+	if err == nil {
+		return DispositionAck
+	}
+	xerr := xerror.From(err)
+	for _, rule := range c.Rules {
+		if rule.matches(xerr) {
+			return rule.Disposition
+		}
+	}
+	return classify(xerr)
+}
+
+// Classify returns the disposition of the message whose handler returned err, based on its status code. If err isn't an
+// xerror, it's converted using xerror.From. The dispositions are:
+//   - DispositionAck: if err is nil, or if the message was a duplicate, i.e. AlreadyExists.
+//   - DispositionRetryWithBackoff: if the error is directly retryable, see xerror.Error.IsDirectlyRetryable, if it's
+//     ResourceExhausted, if it's DeadlineExceeded, or if it's Unknown. Since errors that aren't xerrors are converted
+//     to Unknown errors, this includes errors returned by drivers and clients, ex. network errors, which are often
+//     transient. Messages whose handler keeps failing are expected to be dead-lettered by the broker after a maximum
+//     number of deliveries.
+//   - DispositionRetryNow: if the error is otherwise retryable at a higher level, i.e. Aborted, see
+//     xerror.Error.IsRetryableAtHigherLevel, since handling the message again is that higher level, or if it's
+//     Canceled.
+//   - DispositionDeadLetter: for any other error, since retrying won't help, ex. InvalidArgument or Internal.
+//
+// Use a Classifier to configure the disposition of specific errors.
+func Classify(err error) Disposition {
+	if err == nil {
+		return DispositionAck
+	}
+	return classify(xerror.From(err))
+}
+
+func classify(xerr *xerror.Error) Disposition {
+	switch code := xerr.StatusCode(); {
+	case code == codes.OK, code == codes.AlreadyExists:
+		return DispositionAck
+	case xerr.IsDirectlyRetryable(), code == codes.ResourceExhausted, code == codes.DeadlineExceeded,
+		code == codes.Unknown:
+		return DispositionRetryWithBackoff
+	case xerr.IsRetryableAtHigherLevel(), code == codes.Canceled:
+		return DispositionRetryNow
+	default:
+		return DispositionDeadLetter
+	}
+}
+
+func (r Rule) matches(xerr *xerror.Error) bool {
+	if r.Code != codes.OK && r.Code != xerr.StatusCode() {
+		return false
+	}
+	if r.Domain == "" && r.Reason == "" {
+		return true
+	}
+	info := xerr.ErrorInfo()
+	if !info.Valid {
+		return false
+	}
+	return (r.Domain == "" || r.Domain == info.Value.Domain) && (r.Reason == "" || r.Reason == info.Value.Reason)
+}
@@ -0,0 +1,128 @@
+package xmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Disposition
+	}{
+		{
+			name: "nil",
+			want: DispositionAck,
+		},
+		{
+			name: "already exists",
+			err:  xerror.NewAlreadyExists(xerror.ResourceInfo{ResourceType: "order"}),
+			want: DispositionAck,
+		},
+		{
+			name: "unavailable",
+			err:  xerror.NewUnavailable(errors.New("connection refused")),
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "resource exhausted",
+			err:  xerror.NewQuotaFailure("project:1", "quota exceeded"),
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "deadline exceeded",
+			err:  context.DeadlineExceeded,
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "aborted",
+			err:  xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "ABORTED"}),
+			want: DispositionRetryNow,
+		},
+		{
+			name: "canceled",
+			err:  fmt.Errorf("wrapped: %w", context.Canceled),
+			want: DispositionRetryNow,
+		},
+		{
+			name: "invalid argument",
+			err:  xerror.NewInvalidArgument("age", "must be greater than 0"),
+			want: DispositionDeadLetter,
+		},
+		{
+			name: "unknown",
+			err:  xerror.NewUnknown(errors.New("unexpected EOF")),
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "non-xerror",
+			err:  fmt.Errorf("publishing order: %w", errors.New("dial tcp 10.0.0.1:4222: connection refused")),
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "internal",
+			err:  xerror.NewInternal(errors.New("nil pointer dereference")),
+			want: DispositionDeadLetter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestClassifier_Classify(t *testing.T) {
+	xerror.Init("myservice.example.com")
+	classifier := Classifier{
+		Rules: []Rule{
+			{Domain: "payment.example.com", Reason: "CARD_DECLINED", Disposition: DispositionAck},
+			{Code: codes.NotFound, Disposition: DispositionRetryWithBackoff},
+			{Code: codes.Unknown, Disposition: DispositionDeadLetter},
+		},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want Disposition
+	}{
+		{
+			name: "matching domain and reason",
+			err: xerror.NewPreconditionFailure("card", "DECLINED", "card declined").
+				SetErrorInfo("payment.example.com", "CARD_DECLINED", nil),
+			want: DispositionAck,
+		},
+		{
+			name: "matching code",
+			err:  xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "order", ResourceName: "orders/1"}),
+			want: DispositionRetryWithBackoff,
+		},
+		{
+			name: "non-xerror matching code",
+			err:  errors.New("unsupported message version"),
+			want: DispositionDeadLetter,
+		},
+		{
+			name: "no matching rule",
+			err:  xerror.NewPreconditionFailure("card", "EXPIRED", "card expired"),
+			want: DispositionDeadLetter,
+		},
+		{
+			name: "nil",
+			want: DispositionAck,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, classifier.Classify(tt.err))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package xmq
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tobbstr/xerror"
+)
+
+// The headers that HeadersFrom encodes an error into.
+const (
+	// HeaderCode holds the status code name in upper snake case, ex. "NOT_FOUND".
+	HeaderCode = "xerror-code"
+	// HeaderMessage holds the status message, with line breaks replaced by spaces.
+	HeaderMessage = "xerror-message"
+	// HeaderDomain holds the domain of the error info detail, if any.
+	HeaderDomain = "xerror-domain"
+	// HeaderReason holds the reason of the error info detail, if any.
+	HeaderReason = "xerror-reason"
+	// HeaderEnvelope holds the complete error as a base64 encoded xerrorpb.ErrorEnvelope, see xerror.Error.MarshalBinary.
+	HeaderEnvelope = "xerror-envelope"
+)
+
+// ErrNoErrorHeaders is returned by ErrorFromHeaders when the headers don't carry an error.
+var ErrNoErrorHeaders = errors.New("no error headers")
+
+// HeadersFrom encodes the error into message headers, which is meant for messages that are moved to a dead-letter
+// queue, see DispositionDeadLetter. If err isn't an xerror, it's converted using xerror.From. If err is nil, then it
+// returns nil.
+//
+// Besides the human-readable headers, the complete error is encoded in HeaderEnvelope, including its details and
+// runtime state, so it can be restored using ErrorFromHeaders when the dead-lettered message is inspected or
+// reprocessed. Sensitive runtime state values are masked, see xerror.Var.SafeValue, but details aren't removed even if
+// they are hidden, since dead-letter queues are internal.
+//
+// Ex.
+//
+//	for k, v := range xmq.HeadersFrom(err) {
+//	  msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+//	}
+func HeadersFrom(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+	xerr := xerror.From(err)
+	headers := map[string]string{
+		HeaderCode:    xerror.CodeInfoOf(xerr.StatusCode()).Name,
+		HeaderMessage: strings.Join(strings.Fields(xerr.StatusMessage()), " "),
+	}
+	if info := xerr.ErrorInfo(); info.Valid {
+		headers[HeaderDomain] = info.Value.Domain
+		headers[HeaderReason] = info.Value.Reason
+	}
+	if b, err := xerr.MarshalBinary(); err == nil {
+		headers[HeaderEnvelope] = base64.StdEncoding.EncodeToString(b)
+	}
+	return headers
+}
+
+// ErrorFromHeaders restores the error encoded into message headers by HeadersFrom. It returns ErrNoErrorHeaders if the
+// headers don't carry an error.
+func ErrorFromHeaders(headers map[string]string) (*xerror.Error, error) {
+	encoded, ok := headers[HeaderEnvelope]
+	if !ok {
+		return nil, ErrNoErrorHeaders
+	}
+	b, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s header: %w", HeaderEnvelope, err)
+	}
+	xerr := &xerror.Error{}
+	if err := xerr.UnmarshalBinary(b); err != nil {
+		return nil, fmt.Errorf("unmarshalling %s header: %w", HeaderEnvelope, err)
+	}
+	return xerr, nil
+}
@@ -0,0 +1,59 @@
+package xmq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+func TestHeadersFrom(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	xerror.Init("myservice.example.com")
+	err := xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("version\nmismatch"), Reason: "VERSION_MISMATCH"}).
+		SetDebugInfo("revision 3 != 4", nil).
+		AddVar("order_id", "order-1").
+		HideDetails()
+
+	/* ---------------------------------- When ---------------------------------- */
+	headers := HeadersFrom(err)
+	got, restoreErr := ErrorFromHeaders(headers)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal("ABORTED", headers[HeaderCode])
+	require.Equal("version mismatch", headers[HeaderMessage])
+	require.Equal("myservice.example.com", headers[HeaderDomain])
+	require.Equal("VERSION_MISMATCH", headers[HeaderReason])
+
+	require.NoError(restoreErr)
+	require.Equal(codes.Aborted, got.StatusCode())
+	require.Equal("revision 3 != 4", got.DebugInfo().Value.Detail)
+	require.Equal([]xerror.Var{{Name: "order_id", Value: "order-1"}}, got.RuntimeState())
+	require.True(got.IsDetailsHidden())
+}
+
+func TestHeadersFrom_Canceled(t *testing.T) {
+	/* ---------------------------------- When ---------------------------------- */
+	headers := HeadersFrom(context.Canceled)
+	got, err := ErrorFromHeaders(headers)
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	require.Equal("CANCELLED", headers[HeaderCode])
+	require.NoError(err)
+	require.Equal(codes.Canceled, got.StatusCode())
+}
+
+func TestErrorFromHeaders(t *testing.T) {
+	_, err := ErrorFromHeaders(map[string]string{"other": "header"})
+	require.ErrorIs(t, err, ErrNoErrorHeaders)
+
+	_, err = ErrorFromHeaders(map[string]string{HeaderEnvelope: "not base64!"})
+	require.Error(t, err)
+
+	require.Nil(t, HeadersFrom(nil))
+}
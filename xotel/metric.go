@@ -0,0 +1,75 @@
+/*
+Package xotel integrates xerrors with OpenTelemetry.
+*/
+package xotel
+
+import (
+	"context"
+
+	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xmetrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultCounterName is the default name of the counter.
+const DefaultCounterName = "xerror.errors"
+
+// Attribute keys used by the MetricsObserver.
+const (
+	AttrCode     = attribute.Key("xerror.code")
+	AttrDomain   = attribute.Key("xerror.domain")
+	AttrReason   = attribute.Key("xerror.reason")
+	AttrLogLevel = attribute.Key("xerror.log_level")
+)
+
+// MetricsOptions controls the counter of a MetricsObserver.
+type MetricsOptions struct {
+	// CounterName is the name of the counter. If it's empty, DefaultCounterName is used.
+	CounterName string
+	// Reasons controls the cardinality guard of the reason attribute.
+	Reasons xmetrics.Options
+}
+
+// MetricsObserver is an xerror.Observer that counts errors with the attributes AttrCode, AttrDomain, AttrReason and
+// AttrLogLevel.
+//
+// Ex.
+//
+//	observer, err := xotel.NewMetricsObserver(otel.Meter("myservice"), xotel.MetricsOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	xerror.RegisterObserver(observer, xerror.ObserverOptions{})
+type MetricsObserver struct {
+	counter metric.Int64Counter
+	labeler *xmetrics.Labeler
+}
+
+// NewMetricsObserver returns a new MetricsObserver whose counter is created by the meter.
+func NewMetricsObserver(meter metric.Meter, opts MetricsOptions) (*MetricsObserver, error) {
+	name := opts.CounterName
+	if name == "" {
+		name = DefaultCounterName
+	}
+	counter, err := meter.Int64Counter(
+		name,
+		metric.WithDescription("The number of errors, partitioned by status code, error domain, error reason and log level."),
+		metric.WithUnit("{error}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &MetricsObserver{counter: counter, labeler: xmetrics.NewLabeler(opts.Reasons)}, nil
+}
+
+// ObserveError increments the counter for the error's attributes.
+func (o *MetricsObserver) ObserveError(ctx context.Context, xerr *xerror.Error) {
+	labels := o.labeler.LabelsFrom(xerr)
+	o.counter.Add(ctx, 1, metric.WithAttributes(
+		AttrCode.String(labels.Code),
+		AttrDomain.String(labels.Domain),
+		AttrReason.String(labels.Reason),
+		AttrLogLevel.String(labels.LogLevel),
+	))
+}
@@ -0,0 +1,63 @@
+package xotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricsObserver_ObserveError(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	/* ---------------------------------- Given --------------------------------- */
+	require := require.New(t)
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	observer, err := NewMetricsObserver(provider.Meter("test"), MetricsOptions{})
+	require.NoError(err)
+
+	/* ---------------------------------- When ---------------------------------- */
+	ctx := context.Background()
+	observer.ObserveError(ctx, xerror.NewInternal(errors.New("boom")))
+	observer.ObserveError(ctx, xerror.NewInternal(errors.New("boom again")))
+	observer.ObserveError(ctx, xerror.NewAborted(xerror.ErrorInfoOptions{
+		Error:  errors.New("conflict"),
+		Reason: "VERSION_MISMATCH",
+	}))
+
+	/* ---------------------------------- Then ---------------------------------- */
+	var rm metricdata.ResourceMetrics
+	require.NoError(reader.Collect(ctx, &rm))
+	require.Len(rm.ScopeMetrics, 1)
+	require.Len(rm.ScopeMetrics[0].Metrics, 1)
+	m := rm.ScopeMetrics[0].Metrics[0]
+	require.Equal(DefaultCounterName, m.Name)
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(ok)
+
+	got := make(map[attribute.Set]int64, len(sum.DataPoints))
+	for _, dp := range sum.DataPoints {
+		got[dp.Attributes] = dp.Value
+	}
+	want := map[attribute.Set]int64{
+		attribute.NewSet(
+			AttrCode.String("INTERNAL"),
+			AttrDomain.String(""),
+			AttrReason.String(""),
+			AttrLogLevel.String("error"),
+		): 2,
+		attribute.NewSet(
+			AttrCode.String("ABORTED"),
+			AttrDomain.String("myservice.example.com"),
+			AttrReason.String("VERSION_MISMATCH"),
+			AttrLogLevel.String("warn"),
+		): 1,
+	}
+	require.Equal(want, got)
+}
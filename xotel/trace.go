@@ -0,0 +1,143 @@
+package xotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tobbstr/xerror"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used when recording errors on spans, in addition to AttrCode, AttrDomain and AttrReason.
+const (
+	AttrMessage              = attribute.Key("xerror.message")
+	AttrFieldViolationFields = attribute.Key("xerror.field_violations.field")
+	AttrFieldViolationDescs  = attribute.Key("xerror.field_violations.description")
+	AttrResourceInfoTypes    = attribute.Key("xerror.resource_infos.type")
+	AttrResourceInfoNames    = attribute.Key("xerror.resource_infos.name")
+	// AttrVarPrefix is the prefix of the attribute keys of runtime state variables. Ex. "xerror.var.user_id".
+	AttrVarPrefix = "xerror.var."
+)
+
+// TraceOptions controls how errors are recorded on spans.
+type TraceOptions struct {
+	// RecordVars makes the runtime state variables of the error become span attributes. Each variable is recorded
+	// using the key AttrVarPrefix + the variable's name, and its safe value (see xerror.Var.SafeValue).
+	RecordVars bool
+}
+
+// RecordError records the error on the span in the context, if there is one that is recording. The span's status is
+// set to Error and an exception event is added containing the error's code, message, domain and reason. Any bad
+// request field violations and resource infos are added as span attributes.
+//
+// If err isn't an *xerror.Error, it is converted using xerror.From(). If err is nil, then it's a no-op.
+func RecordError(ctx context.Context, err error, opts TraceOptions) {
+	if err == nil {
+		return
+	}
+	recordError(ctx, xerror.From(err), opts)
+}
+
+// TraceObserver is an xerror.Observer that records the observed errors on the span in the context. When registered,
+// the errors returned by the xgrpc interceptor and the xhttp responders are recorded on the server spans, given that
+// the spans are started by an instrumentation such as otelgrpc or otelhttp.
+//
+// Ex.
+//
+//	xerror.RegisterObserver(xotel.NewTraceObserver(xotel.TraceOptions{}), xerror.ObserverOptions{})
+type TraceObserver struct {
+	opts TraceOptions
+}
+
+// NewTraceObserver returns a new TraceObserver.
+func NewTraceObserver(opts TraceOptions) *TraceObserver {
+	return &TraceObserver{opts: opts}
+}
+
+// ObserveError records the error on the span in the context.
+func (o *TraceObserver) ObserveError(ctx context.Context, xerr *xerror.Error) {
+	recordError(ctx, xerr, o.opts)
+}
+
+func recordError(ctx context.Context, xerr *xerror.Error, opts TraceOptions) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	eventAttrs := []attribute.KeyValue{
+		AttrCode.String(xerror.CodeInfoOf(xerr.StatusCode()).Name),
+		AttrMessage.String(xerr.StatusMessage()),
+	}
+	if info := xerr.ErrorInfo(); info.Valid {
+		eventAttrs = append(eventAttrs, AttrDomain.String(info.Value.Domain), AttrReason.String(info.Value.Reason))
+	}
+	span.RecordError(xerr, trace.WithAttributes(eventAttrs...))
+	span.SetStatus(otelcodes.Error, xerr.StatusMessage())
+
+	var spanAttrs []attribute.KeyValue
+	if violations := xerr.BadRequestViolations(); len(violations) > 0 {
+		fields := make([]string, len(violations))
+		descs := make([]string, len(violations))
+		for i, v := range violations {
+			fields[i] = v.Field
+			descs[i] = v.Description
+		}
+		spanAttrs = append(spanAttrs, AttrFieldViolationFields.StringSlice(fields), AttrFieldViolationDescs.StringSlice(descs))
+	}
+	if infos := xerr.ResourceInfos(); len(infos) > 0 {
+		types := make([]string, len(infos))
+		names := make([]string, len(infos))
+		for i, info := range infos {
+			types[i] = info.ResourceType
+			names[i] = info.ResourceName
+		}
+		spanAttrs = append(spanAttrs, AttrResourceInfoTypes.StringSlice(types), AttrResourceInfoNames.StringSlice(names))
+	}
+	if opts.RecordVars {
+		for _, v := range xerr.RuntimeState() {
+			spanAttrs = append(spanAttrs, attributeFrom(AttrVarPrefix+v.Name, v.SafeValue()))
+		}
+	}
+	span.SetAttributes(spanAttrs...)
+}
+
+// attributeFrom returns an attribute with the value's type if it's supported, otherwise the value is formatted as a
+// string.
+func attributeFrom(key string, value any) attribute.KeyValue {
+	k := attribute.Key(key)
+	switch v := value.(type) {
+	case string:
+		return k.String(v)
+	case bool:
+		return k.Bool(v)
+	case int:
+		return k.Int(v)
+	case int64:
+		return k.Int64(v)
+	case float64:
+		return k.Float64(v)
+	case []string:
+		return k.StringSlice(v)
+	case fmt.Stringer:
+		return k.String(v.String())
+	default:
+		return k.String(fmt.Sprintf("%v", v))
+	}
+}
+
+// ExtractTraceContext returns the trace context of the span in ctx, and false if the span context isn't valid. It is
+// meant to be used with xerror.InitTraceContext.
+//
+// Ex.
+//
+//	xerror.InitTraceContext(xerror.TraceContextOptions{Extract: xotel.ExtractTraceContext})
+func ExtractTraceContext(ctx context.Context) (xerror.TraceContext, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return xerror.TraceContext{}, false
+	}
+	return xerror.TraceContext{TraceID: sc.TraceID().String(), SpanID: sc.SpanID().String()}, true
+}
@@ -0,0 +1,321 @@
+package xotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestRecordError(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type given struct {
+		err  error
+		opts TraceOptions
+	}
+	type want struct {
+		eventAttrs []attribute.KeyValue
+		spanAttrs  []attribute.KeyValue
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name: "error with error info",
+			given: given{
+				err: xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}),
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("ABORTED"),
+					AttrMessage.String("conflict"),
+					AttrDomain.String("myservice.example.com"),
+					AttrReason.String("VERSION_MISMATCH"),
+				},
+			},
+		},
+		{
+			name: "error with field violations",
+			given: given{
+				err: xerror.NewInvalidArgumentBatch([]xerror.BadRequestViolation{
+					{Field: "age", Description: "must be greater than 0"},
+					{Field: "name", Description: "cannot be empty"},
+				}),
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("INVALID_ARGUMENT"),
+					AttrMessage.String("one or more request arguments were invalid"),
+				},
+				spanAttrs: []attribute.KeyValue{
+					AttrFieldViolationFields.StringSlice([]string{"age", "name"}),
+					AttrFieldViolationDescs.StringSlice([]string{"must be greater than 0", "cannot be empty"}),
+				},
+			},
+		},
+		{
+			name: "error with resource infos",
+			given: given{
+				err: xerror.NewNotFound(xerror.ResourceInfo{ResourceName: "users/123", ResourceType: "User"}),
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("NOT_FOUND"),
+					AttrMessage.String("requested resource not found"),
+				},
+				spanAttrs: []attribute.KeyValue{
+					AttrResourceInfoTypes.StringSlice([]string{"User"}),
+					AttrResourceInfoNames.StringSlice([]string{"users/123"}),
+				},
+			},
+		},
+		{
+			name: "runtime state is not recorded by default",
+			given: given{
+				err: xerror.NewInternal(errors.New("boom")).AddVar("user_id", "123"),
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("INTERNAL"),
+					AttrMessage.String("boom"),
+				},
+			},
+		},
+		{
+			name: "runtime state is recorded",
+			given: given{
+				err:  xerror.NewInternal(errors.New("boom")).AddVar("user_id", "123").AddVar("attempt", 2),
+				opts: TraceOptions{RecordVars: true},
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("INTERNAL"),
+					AttrMessage.String("boom"),
+				},
+				spanAttrs: []attribute.KeyValue{
+					attribute.String("xerror.var.user_id", "123"),
+					attribute.Int("xerror.var.attempt", 2),
+				},
+			},
+		},
+		{
+			name: "sensitive runtime state is masked",
+			given: given{
+				err:  xerror.NewInternal(errors.New("boom")).AddSecretVar("token", "super-secret-token"),
+				opts: TraceOptions{RecordVars: true},
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("INTERNAL"),
+					AttrMessage.String("boom"),
+				},
+				spanAttrs: []attribute.KeyValue{
+					attribute.String("xerror.var.token", xerror.RedactedValue),
+				},
+			},
+		},
+		{
+			name: "non-xerror",
+			given: given{
+				err: errors.New("something unexpected happened"),
+			},
+			want: want{
+				eventAttrs: []attribute.KeyValue{
+					AttrCode.String("UNKNOWN"),
+					AttrMessage.String("something unknown happened"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			ctx, span := provider.Tracer("test").Start(context.Background(), "handler")
+
+			/* ---------------------------------- When ---------------------------------- */
+			RecordError(ctx, tt.given.err, tt.given.opts)
+			span.End()
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			spans := exporter.GetSpans()
+			require.Len(spans, 1)
+			got := spans[0]
+			require.Equal(otelcodes.Error, got.Status.Code)
+			require.Len(got.Events, 1)
+			require.Equal("exception", got.Events[0].Name)
+			for _, attr := range tt.want.eventAttrs {
+				require.Contains(got.Events[0].Attributes, attr)
+			}
+			require.ElementsMatch(tt.want.spanAttrs, got.Attributes)
+		})
+	}
+}
+
+func TestTraceObserver(t *testing.T) {
+	/* ---------------------------------- Given --------------------------------- */
+	xerror.RegisterObserver(NewTraceObserver(TraceOptions{}), xerror.ObserverOptions{})
+	defer xerror.ResetObservers()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "handler")
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, xerror.NewAborted(xerror.ErrorInfoOptions{Error: errors.New("conflict"), Reason: "VERSION_MISMATCH"}).
+			HideDetails()
+	}
+
+	/* ---------------------------------- When ---------------------------------- */
+	_, _ = xgrpc.UnaryXErrorInterceptor(ctx, nil, nil, handler)
+	span.End()
+
+	/* ---------------------------------- Then ---------------------------------- */
+	require := require.New(t)
+	spans := exporter.GetSpans()
+	require.Len(spans, 1)
+	require.Equal(otelcodes.Error, spans[0].Status.Code)
+	require.Equal("conflict", spans[0].Status.Description)
+	require.Len(spans[0].Events, 1)
+	require.Contains(spans[0].Events[0].Attributes, AttrReason.String("VERSION_MISMATCH"))
+}
+
+func TestExtractTraceContext_WithContext(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	type given struct {
+		ctx      context.Context
+		xerr     *xerror.Error
+		exposure xerror.TraceExposure
+	}
+	type want struct {
+		traceContext xerror.Optional[xerror.TraceContext]
+		debugInfo    xerror.Optional[xerror.DebugInfo]
+		requestInfo  bool
+	}
+	validTraceContext := xerror.Optional[xerror.TraceContext]{
+		Value: xerror.TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7"},
+		Valid: true,
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  want
+	}{
+		{
+			name: "no span in context",
+			given: given{
+				ctx:  context.Background(),
+				xerr: xerror.NewNotImplemented(),
+			},
+		},
+		{
+			name: "trace context is not exposed",
+			given: given{
+				ctx:  spanCtx,
+				xerr: xerror.NewNotImplemented(),
+			},
+			want: want{traceContext: validTraceContext},
+		},
+		{
+			name: "trace context is exposed in request info",
+			given: given{
+				ctx:      spanCtx,
+				xerr:     xerror.NewNotImplemented(),
+				exposure: xerror.TraceExposureRequestInfo,
+			},
+			want: want{traceContext: validTraceContext, requestInfo: true},
+		},
+		{
+			name: "trace context is exposed in new debug info",
+			given: given{
+				ctx:      spanCtx,
+				xerr:     xerror.NewNotImplemented(),
+				exposure: xerror.TraceExposureDebugInfo,
+			},
+			want: want{
+				traceContext: validTraceContext,
+				debugInfo: xerror.Optional[xerror.DebugInfo]{
+					Value: xerror.DebugInfo{Detail: "trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7"},
+					Valid: true,
+				},
+			},
+		},
+		{
+			name: "trace context is exposed in existing debug info",
+			given: given{
+				ctx:      spanCtx,
+				xerr:     xerror.NewNotImplemented().SetDebugInfo("some detail", []string{"line 1"}),
+				exposure: xerror.TraceExposureDebugInfo,
+			},
+			want: want{
+				traceContext: validTraceContext,
+				debugInfo: xerror.Optional[xerror.DebugInfo]{
+					Value: xerror.DebugInfo{
+						Detail:       "some detail (trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7)",
+						StackEntries: []string{"line 1"},
+					},
+					Valid: true,
+				},
+			},
+		},
+		{
+			name: "trace context is not exposed when details are hidden",
+			given: given{
+				ctx:      spanCtx,
+				xerr:     xerror.NewNotImplemented().HideDetails(),
+				exposure: xerror.TraceExposureRequestInfo,
+			},
+			want: want{traceContext: validTraceContext},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			xerror.InitTraceContext(xerror.TraceContextOptions{Extract: ExtractTraceContext, Exposure: tt.given.exposure})
+			defer xerror.InitTraceContext(xerror.TraceContextOptions{})
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := tt.given.xerr.WithContext(tt.given.ctx)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			require.Equal(tt.want.traceContext, got.TraceContext())
+			require.Equal(tt.want.debugInfo, got.DebugInfo())
+			var hasRequestInfo bool
+			for _, detail := range got.Status().Details() {
+				if info, ok := detail.(*errdetails.RequestInfo); ok {
+					hasRequestInfo = true
+					require.Equal("4bf92f3577b34da6a3ce929d0e0e4736", info.RequestId)
+				}
+			}
+			require.Equal(tt.want.requestInfo, hasRequestInfo)
+
+			// The request info must never reach a caller when the details are hidden
+			_ = got.HideDetails().RemoveSensitiveDetails()
+			require.Empty(got.Status().Details())
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Package xprometheus provides an xerror.Observer that counts xerrors using a Prometheus counter.
+*/
+package xprometheus
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tobbstr/xerror"
+	"github.com/tobbstr/xerror/xmetrics"
+)
+
+// DefaultName is the default name of the counter.
+const DefaultName = "xerror_errors_total"
+
+// Options controls the counter of an Observer.
+type Options struct {
+	// Namespace, Subsystem and Name make up the fully-qualified name of the counter. If Name is empty, DefaultName is
+	// used.
+	Namespace string
+	Subsystem string
+	Name      string
+	// ConstLabels are labels with fixed values added to the counter.
+	ConstLabels prometheus.Labels
+	// Reasons controls the cardinality guard of the reason label.
+	Reasons xmetrics.Options
+}
+
+// Observer is an xerror.Observer that counts errors labelled by "code", "domain", "reason" and "log_level". It is also
+// a prometheus.Collector, so it must be registered with a Prometheus registry.
+//
+// Ex.
+//
+//	observer := xprometheus.NewObserver(xprometheus.Options{Namespace: "myservice"})
+//	prometheus.MustRegister(observer)
+//	xerror.RegisterObserver(observer, xerror.ObserverOptions{})
+type Observer struct {
+	counter *prometheus.CounterVec
+	labeler *xmetrics.Labeler
+}
+
+// NewObserver returns a new Observer.
+func NewObserver(opts Options) *Observer {
+	name := opts.Name
+	if name == "" {
+		name = DefaultName
+	}
+	return &Observer{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Subsystem:   opts.Subsystem,
+			Name:        name,
+			Help:        "The number of errors, partitioned by status code, error domain, error reason and log level.",
+			ConstLabels: opts.ConstLabels,
+		}, []string{"code", "domain", "reason", "log_level"}),
+		labeler: xmetrics.NewLabeler(opts.Reasons),
+	}
+}
+
+// ObserveError increments the counter for the error's labels.
+func (o *Observer) ObserveError(_ context.Context, xerr *xerror.Error) {
+	labels := o.labeler.LabelsFrom(xerr)
+	o.counter.WithLabelValues(labels.Code, labels.Domain, labels.Reason, labels.LogLevel).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	o.counter.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	o.counter.Collect(ch)
+}
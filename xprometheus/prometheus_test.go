@@ -0,0 +1,36 @@
+package xprometheus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+)
+
+func TestObserver_ObserveError(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	/* ---------------------------------- Given --------------------------------- */
+	observer := NewObserver(Options{Namespace: "myservice"})
+
+	/* ---------------------------------- When ---------------------------------- */
+	observer.ObserveError(context.Background(), xerror.NewInternal(errors.New("boom")))
+	observer.ObserveError(context.Background(), xerror.NewInternal(errors.New("boom again")))
+	observer.ObserveError(context.Background(), xerror.NewAborted(xerror.ErrorInfoOptions{
+		Error:  errors.New("conflict"),
+		Reason: "VERSION_MISMATCH",
+	}))
+
+	/* ---------------------------------- Then ---------------------------------- */
+	want := `
+# HELP myservice_xerror_errors_total The number of errors, partitioned by status code, error domain, error reason and log level.
+# TYPE myservice_xerror_errors_total counter
+myservice_xerror_errors_total{code="ABORTED",domain="myservice.example.com",log_level="warn",reason="VERSION_MISMATCH"} 1
+myservice_xerror_errors_total{code="INTERNAL",domain="",log_level="error",reason=""} 2
+`
+	require.NoError(t, testutil.CollectAndCompare(observer, strings.NewReader(want)))
+}
@@ -0,0 +1,160 @@
+/*
+Package xtwirp integrates xerrors with Twirp, see github.com/twitchtv/twirp.
+*/
+package xtwirp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/tobbstr/xerror"
+	"github.com/twitchtv/twirp"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// MetaDetails is the key of the Twirp error meta holding the status details, encoded as a JSON array in the protobuf
+// JSON format, ex. [{"@type": "type.googleapis.com/google.rpc.ResourceInfo", "resourceType": "user"}].
+const MetaDetails = "details"
+
+// errorCodes maps status codes to Twirp error codes. The Twirp error codes are named after the status codes, except for
+// the ones that only exist in Twirp.
+var errorCodes = map[codes.Code]twirp.ErrorCode{
+	codes.Canceled:           twirp.Canceled,
+	codes.Unknown:            twirp.Unknown,
+	codes.InvalidArgument:    twirp.InvalidArgument,
+	codes.DeadlineExceeded:   twirp.DeadlineExceeded,
+	codes.NotFound:           twirp.NotFound,
+	codes.AlreadyExists:      twirp.AlreadyExists,
+	codes.PermissionDenied:   twirp.PermissionDenied,
+	codes.ResourceExhausted:  twirp.ResourceExhausted,
+	codes.FailedPrecondition: twirp.FailedPrecondition,
+	codes.Aborted:            twirp.Aborted,
+	codes.OutOfRange:         twirp.OutOfRange,
+	codes.Unimplemented:      twirp.Unimplemented,
+	codes.Internal:           twirp.Internal,
+	codes.Unavailable:        twirp.Unavailable,
+	codes.DataLoss:           twirp.DataLoss,
+	codes.Unauthenticated:    twirp.Unauthenticated,
+}
+
+// NewInterceptor returns a Twirp server interceptor that converts the errors returned by handlers to Twirp errors, see
+// TwirpErrorFrom.
+//
+// Ex.
+//
+//	handler := orderv1.NewOrderServiceServer(server, twirp.WithServerInterceptors(xtwirp.NewInterceptor()))
+func NewInterceptor() twirp.Interceptor {
+	return func(next twirp.Method) twirp.Method {
+		return func(ctx context.Context, req any) (any, error) {
+			ctx = xerror.WithVarScope(ctx)
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, TwirpErrorFrom(ctx, err)
+			}
+			return resp, nil
+		}
+	}
+}
+
+// TwirpErrorFrom converts the error to a Twirp error that's returned to a caller. The Twirp error code corresponds to
+// the status code, and the status details are kept in the MetaDetails meta.
+//
+// Just like xhttp.RespondFailedNegotiated, the error is converted using xerror.From if it isn't an xerror, it's
+// enriched with the values carried by ctx (see xerror.Error.WithContext), and it's passed to the observers registered
+// with xerror.RegisterObserver. Sensitive details are removed if the error is marked as hidden. Twirp errors are
+// returned as is.
+func TwirpErrorFrom(ctx context.Context, err error) twirp.Error {
+	var twirpErr twirp.Error
+	if errors.As(err, &twirpErr) {
+		return twirpErr
+	}
+	xerr := xerror.From(err)
+	_ = xerr.WithContext(ctx)
+	xerror.ObserveResponded(ctx, xerr)
+	if xerr.IsDetailsHidden() {
+		_ = xerr.RemoveSensitiveDetails()
+	}
+	_ = xerr.RemoveProvenance()
+
+	code, ok := errorCodes[xerr.StatusCode()]
+	if !ok {
+		code = twirp.Unknown
+	}
+	twirpErr = twirp.NewError(code, xerr.StatusMessage())
+	st := xerr.StatusProto()
+	if len(st.GetDetails()) == 0 {
+		return twirpErr
+	}
+	details := make([]json.RawMessage, len(st.GetDetails()))
+	for i, detail := range st.GetDetails() {
+		b, err := protojson.Marshal(detail)
+		if err != nil {
+			return twirpErr
+		}
+		details[i] = b
+	}
+	b, err := json.Marshal(details)
+	if err != nil {
+		return twirpErr
+	}
+	return twirpErr.WithMeta(MetaDetails, string(b))
+}
+
+// ErrorFrom is a convenience function that creates a new xerror from an error returned by a Twirp client, keeping its
+// code, message and the details in the MetaDetails meta. The Twirp error codes that only exist in Twirp are converted
+// to the closest status codes, i.e. malformed to InvalidArgument and bad_route to Unimplemented. If the error isn't a
+// Twirp error, then it returns an xerror with the status code Unknown. If the error already is an xerror, then it is
+// returned as is.
+//
+// Ex.
+//
+//	resp, err := client.CreateOrder(ctx, req)
+//	if err != nil {
+//	  return xtwirp.ErrorFrom(err).AddVar("customer_id", req.CustomerId)
+//	}
+func ErrorFrom(err error) *xerror.Error {
+	if err == nil {
+		return nil
+	}
+	var xerr *xerror.Error
+	if errors.As(err, &xerr) {
+		return xerr
+	}
+	var twirpErr twirp.Error
+	if !errors.As(err, &twirpErr) {
+		return new(xerror.Error).SetStatus(status.New(codes.Unknown, err.Error()))
+	}
+
+	st := &spb.Status{Code: int32(codeFrom(twirpErr.Code())), Message: twirpErr.Msg()}
+	var details []json.RawMessage
+	if err := json.Unmarshal([]byte(twirpErr.Meta(MetaDetails)), &details); err == nil {
+		for _, raw := range details {
+			detail := &anypb.Any{}
+			// Details of unknown types are left out
+			if err := protojson.Unmarshal(raw, detail); err == nil {
+				st.Details = append(st.Details, detail)
+			}
+		}
+	}
+	return new(xerror.Error).SetStatus(status.FromProto(st))
+}
+
+func codeFrom(code twirp.ErrorCode) codes.Code {
+	switch code {
+	case twirp.Malformed:
+		return codes.InvalidArgument
+	case twirp.BadRoute:
+		return codes.Unimplemented
+	}
+	for c, twirpCode := range errorCodes {
+		if twirpCode == code {
+			return c
+		}
+	}
+	return codes.Unknown
+}
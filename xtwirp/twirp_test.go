@@ -0,0 +1,125 @@
+package xtwirp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"github.com/twitchtv/twirp"
+	"google.golang.org/grpc/codes"
+)
+
+func TestTwirpErrorFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type want struct {
+		code        twirp.ErrorCode
+		msg         string
+		metaDetails bool
+	}
+	tests := []struct {
+		name string
+		err  error
+		want want
+	}{
+		{
+			name: "xerror with details",
+			err:  xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"}),
+			want: want{code: twirp.NotFound, msg: "requested resource not found", metaDetails: true},
+		},
+		{
+			name: "xerror with hidden details",
+			err:  xerror.NewInternal(errors.New("boom")).SetDebugInfo("stack", nil),
+			want: want{code: twirp.Internal, msg: "boom"},
+		},
+		{
+			name: "non-xerror",
+			err:  context.Canceled,
+			want: want{code: twirp.Canceled, msg: "request cancelled by the client"},
+		},
+		{
+			name: "twirp error",
+			err:  twirp.NewError(twirp.Malformed, "bad json"),
+			want: want{code: twirp.Malformed, msg: "bad json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := TwirpErrorFrom(context.Background(), tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.Equal(t, tt.want.code, got.Code())
+			require.Equal(t, tt.want.msg, got.Msg())
+			require.Equal(t, tt.want.metaDetails, got.Meta(MetaDetails) != "")
+		})
+	}
+}
+
+func TestErrorFrom(t *testing.T) {
+	xerror.Init("myservice.example.com")
+
+	type want struct {
+		code          codes.Code
+		message       string
+		resourceInfos []xerror.ResourceInfo
+	}
+	tests := []struct {
+		name string
+		err  error
+		want want
+	}{
+		{
+			name: "round trip",
+			err: TwirpErrorFrom(
+				context.Background(),
+				xerror.NewNotFound(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"}),
+			),
+			want: want{
+				code:          codes.NotFound,
+				message:       "requested resource not found",
+				resourceInfos: []xerror.ResourceInfo{{ResourceType: "user", ResourceName: "users/1"}},
+			},
+		},
+		{
+			name: "twirp-only error code",
+			err:  twirp.NewError(twirp.BadRoute, "no such method"),
+			want: want{code: codes.Unimplemented, message: "no such method"},
+		},
+		{
+			name: "malformed details",
+			err:  twirp.NewError(twirp.Aborted, "conflict").WithMeta(MetaDetails, "not json"),
+			want: want{code: codes.Aborted, message: "conflict"},
+		},
+		{
+			name: "non-twirp error",
+			err:  errors.New("some error"),
+			want: want{code: codes.Unknown, message: "some error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := ErrorFrom(tt.err)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require.Equal(t, tt.want.code, got.StatusCode())
+			require.Equal(t, tt.want.message, got.StatusMessage())
+			require.Equal(t, tt.want.resourceInfos, got.ResourceInfos())
+		})
+	}
+}
+
+func TestNewInterceptor(t *testing.T) {
+	method := NewInterceptor()(func(context.Context, any) (any, error) {
+		return nil, xerror.NewAlreadyExists(xerror.ResourceInfo{ResourceType: "user", ResourceName: "users/1"})
+	})
+
+	_, err := method(context.Background(), nil)
+
+	var twirpErr twirp.Error
+	require.ErrorAs(t, err, &twirpErr)
+	require.Equal(t, twirp.AlreadyExists, twirpErr.Code())
+}
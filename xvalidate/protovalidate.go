@@ -0,0 +1,80 @@
+package xvalidate
+
+import (
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/tobbstr/xerror"
+)
+
+// numericRules are the names of the protovalidate rule groups that apply to numbers, timestamps and durations.
+var numericRules = map[string]struct{}{
+	"int32": {}, "int64": {}, "uint32": {}, "uint64": {}, "sint32": {}, "sint64": {},
+	"fixed32": {}, "fixed64": {}, "sfixed32": {}, "sfixed64": {}, "float": {}, "double": {},
+	"timestamp": {}, "duration": {},
+}
+
+// FromProtoViolations converts protovalidate violations into an InvalidArgument error, or into an OutOfRange error if
+// all violations are range violations of numbers, timestamps or durations (the "gt", "gte", "lt" and "lte" rules). If
+// there are no violations, it returns nil. The error is an *xerror.Error, which is returned as an error so that
+// returning the result directly from a function that returns error yields a nil error when there are no violations.
+//
+// The violations are typically obtained from the error returned by protovalidate's Validate function.
+//
+// Ex.
+//
+//	if err := protovalidate.Validate(req); err != nil {
+//		var valErr *protovalidate.ValidationError
+//		if errors.As(err, &valErr) {
+//			return xvalidate.FromProtoViolations(valErr.ToProto(), xvalidate.Options{})
+//		}
+//		return xerror.NewInternal(err)
+//	}
+func FromProtoViolations(violations *validate.Violations, opts Options) error {
+	var converted xerror.Violations
+	for _, v := range violations.GetViolations() {
+		field := opts.Naming.protoFieldPath(v.GetField())
+		if isRangeViolation(v) {
+			converted.AddOutOfRange(field, v.GetMessage())
+			continue
+		}
+		converted.Add(field, v.GetMessage())
+	}
+	return converted.Err()
+}
+
+// protoFieldPath converts a protovalidate field path into a field path, ex. "emailAddresses[3].type[2]".
+func (n FieldNaming) protoFieldPath(fieldPath *validate.FieldPath) string {
+	var path xerror.FieldPath
+	for _, element := range fieldPath.GetElements() {
+		path = path.Field(element.GetFieldName())
+		switch subscript := element.GetSubscript().(type) {
+		case *validate.FieldPathElement_Index:
+			path = path.Index(int(subscript.Index))
+		case *validate.FieldPathElement_BoolKey:
+			path = path.Key(subscript.BoolKey)
+		case *validate.FieldPathElement_IntKey:
+			path = path.Key(subscript.IntKey)
+		case *validate.FieldPathElement_UintKey:
+			path = path.Key(subscript.UintKey)
+		case *validate.FieldPathElement_StringKey:
+			path = path.Key(subscript.StringKey)
+		}
+	}
+	return n.render(path)
+}
+
+// isRangeViolation returns true if the violated rule is a range rule of a number, timestamp or duration. The rule is
+// identified by the last two elements of the rule path, ex. "int32.gt", falling back to the rule id.
+func isRangeViolation(v *validate.Violation) bool {
+	var group, rule string
+	if elements := v.GetRule().GetElements(); len(elements) >= 2 {
+		group, rule = elements[len(elements)-2].GetFieldName(), elements[len(elements)-1].GetFieldName()
+	} else {
+		group, rule, _ = strings.Cut(v.GetRuleId(), ".")
+	}
+	if _, ok := numericRules[group]; !ok {
+		return false
+	}
+	return strings.HasPrefix(rule, "gt") || strings.HasPrefix(rule, "lt")
+}
@@ -0,0 +1,114 @@
+package xvalidate
+
+import (
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromProtoViolations(t *testing.T) {
+	type given struct {
+		violations *validate.Violations
+		opts       Options
+	}
+	type want struct {
+		code       codes.Code
+		violations []xerror.BadRequestViolation
+	}
+	emailTypeViolation := &validate.Violation{
+		Field: fieldPath(
+			&validate.FieldPathElement{FieldName: proto.String("email_addresses"), Subscript: &validate.FieldPathElement_Index{Index: 3}},
+			&validate.FieldPathElement{FieldName: proto.String("type"), Subscript: &validate.FieldPathElement_Index{Index: 2}},
+		),
+		Rule:    fieldPath(&validate.FieldPathElement{FieldName: proto.String("enum")}, &validate.FieldPathElement{FieldName: proto.String("defined_only")}),
+		RuleId:  proto.String("enum.defined_only"),
+		Message: proto.String("value must be one of the defined enum values"),
+	}
+	ageViolation := &validate.Violation{
+		Field:   fieldPath(&validate.FieldPathElement{FieldName: proto.String("age")}),
+		Rule:    fieldPath(&validate.FieldPathElement{FieldName: proto.String("int32")}, &validate.FieldPathElement{FieldName: proto.String("gte")}),
+		RuleId:  proto.String("int32.gte"),
+		Message: proto.String("value must be greater than or equal to 18"),
+	}
+	labelViolation := &validate.Violation{
+		Field:   fieldPath(&validate.FieldPathElement{FieldName: proto.String("labels"), Subscript: &validate.FieldPathElement_StringKey{StringKey: "team"}}),
+		RuleId:  proto.String("string.min_len"),
+		Message: proto.String("value length must be at least 1 characters"),
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  *want
+	}{
+		{
+			name:  "no violations",
+			given: given{violations: &validate.Violations{}},
+		},
+		{
+			name:  "nil violations",
+			given: given{violations: nil},
+		},
+		{
+			name: "invalid arguments with json naming",
+			given: given{
+				violations: &validate.Violations{Violations: []*validate.Violation{emailTypeViolation, ageViolation, labelViolation}},
+			},
+			want: &want{
+				code: codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{
+					{Field: "emailAddresses[3].type[2]", Description: "value must be one of the defined enum values"},
+					{Field: "age", Description: "value must be greater than or equal to 18"},
+					{Field: `labels["team"]`, Description: "value length must be at least 1 characters"},
+				},
+			},
+		},
+		{
+			name: "invalid argument with proto naming",
+			given: given{
+				violations: &validate.Violations{Violations: []*validate.Violation{emailTypeViolation}},
+				opts:       Options{Naming: FieldNamingProto},
+			},
+			want: &want{
+				code: codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{
+					{Field: "email_addresses[3].type[2]", Description: "value must be one of the defined enum values"},
+				},
+			},
+		},
+		{
+			name: "out of range",
+			given: given{
+				violations: &validate.Violations{Violations: []*validate.Violation{ageViolation}},
+			},
+			want: &want{
+				code:       codes.OutOfRange,
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "value must be greater than or equal to 18"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- When ---------------------------------- */
+			got := FromProtoViolations(tt.given.violations, tt.given.opts)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			if tt.want == nil {
+				require.NoError(got)
+				return
+			}
+			var xerr *xerror.Error
+			require.ErrorAs(got, &xerr)
+			require.Equal(tt.want.code, xerr.StatusCode())
+			require.Equal(tt.want.violations, xerr.BadRequestViolations())
+		})
+	}
+}
+
+func fieldPath(elements ...*validate.FieldPathElement) *validate.FieldPath {
+	return &validate.FieldPath{Elements: elements}
+}
@@ -0,0 +1,37 @@
+/*
+Package xvalidate converts validation results into xerrors. It supports the results of the go-playground validator
+(github.com/go-playground/validator) and of protovalidate (buf.validate), and turns them into a single InvalidArgument
+or OutOfRange error with one bad request violation per failed field.
+*/
+package xvalidate
+
+import (
+	"github.com/tobbstr/xerror"
+)
+
+// FieldNaming controls how the field names of the bad request violations are rendered.
+type FieldNaming uint8
+
+const (
+	// FieldNamingJSON renders field names in lowerCamelCase, ex. "emailAddresses[3].type[2]".
+	FieldNamingJSON FieldNaming = iota
+	// FieldNamingProto renders field names in snake_case, ex. "email_addresses[3].type[2]".
+	FieldNamingProto
+)
+
+// Options controls how validation results are converted.
+type Options struct {
+	// Naming controls how the field names of the bad request violations are rendered. The default is
+	// FieldNamingJSON.
+	Naming FieldNaming
+}
+
+// render renders the path according to the naming.
+func (n FieldNaming) render(path xerror.FieldPath) string {
+	switch n {
+	case FieldNamingProto:
+		return path.Proto()
+	default:
+		return path.JSON()
+	}
+}
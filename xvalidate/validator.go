@@ -0,0 +1,135 @@
+package xvalidate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/tobbstr/xerror"
+)
+
+// FromValidator converts the error returned by the go-playground validator, ex. validate.Struct(req), into an
+// InvalidArgument error, or into an OutOfRange error if all failed validations are range validations of numbers (the
+// "gt", "gte", "lt", "lte", "min" and "max" tags). If err is nil, it returns nil. Just like with FromProtoViolations,
+// the error is an *xerror.Error that is returned as an error.
+//
+// The field names are derived from the namespace of each failed field, without the name of the validated struct. If
+// the validator has a tag name function registered, for example one that returns the JSON tag names, then those names
+// are used as the basis instead of the Go field names.
+//
+// If err isn't a validator.ValidationErrors, then the validator was used incorrectly, and an Internal error is
+// returned.
+//
+// Ex.
+//
+//	if err := validate.Struct(req); err != nil {
+//		return xvalidate.FromValidator(err, xvalidate.Options{})
+//	}
+func FromValidator(err error, opts Options) error {
+	if err == nil {
+		return nil
+	}
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return xerror.NewInternal(err)
+	}
+	var violations xerror.Violations
+	for _, fe := range validationErrs {
+		field, description := opts.Naming.validatorFieldPath(fe.Namespace()), describeFieldError(fe)
+		if isRangeFieldError(fe) {
+			violations.AddOutOfRange(field, description)
+			continue
+		}
+		violations.Add(field, description)
+	}
+	return violations.Err()
+}
+
+// validatorFieldPath converts a validator namespace, ex. "CreateContactRequest.EmailAddresses[3].Type[2]", into a
+// field path, ex. "emailAddresses[3].type[2]". Map keys are rendered as with xerror.FieldPath.Key.
+func (n FieldNaming) validatorFieldPath(namespace string) string {
+	_, namespace, found := strings.Cut(namespace, ".")
+	if !found {
+		return ""
+	}
+	var path xerror.FieldPath
+	for _, segment := range strings.Split(namespace, ".") {
+		name, subscripts, _ := strings.Cut(segment, "[")
+		path = path.Field(name)
+		for _, subscript := range strings.Split(strings.TrimSuffix(subscripts, "]"), "][") {
+			if subscript == "" {
+				continue
+			}
+			if i, err := strconv.Atoi(subscript); err == nil {
+				path = path.Index(i)
+				continue
+			}
+			path = path.Key(subscript)
+		}
+	}
+	return n.render(path)
+}
+
+// isRangeFieldError returns true if the field error is a range validation of a number.
+func isRangeFieldError(fe validator.FieldError) bool {
+	switch fe.Tag() {
+	case "gt", "gte", "lt", "lte", "min", "max":
+	default:
+		return false
+	}
+	switch fe.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// describeFieldError returns a description of why the field is bad.
+func describeFieldError(fe validator.FieldError) string {
+	isNumber := isRangeFieldError(fe)
+	switch fe.Tag() {
+	case "required":
+		return "must be set"
+	case "email":
+		return "must be a valid email address"
+	case "url", "uri":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return fmt.Sprintf("must be one of [%s]", fe.Param())
+	case "len":
+		return fmt.Sprintf("must have a length of %s", fe.Param())
+	case "min", "gte":
+		if isNumber {
+			return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+		}
+		return fmt.Sprintf("must have a length of at least %s", fe.Param())
+	case "max", "lte":
+		if isNumber {
+			return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+		}
+		return fmt.Sprintf("must have a length of at most %s", fe.Param())
+	case "gt":
+		if isNumber {
+			return fmt.Sprintf("must be greater than %s", fe.Param())
+		}
+		return fmt.Sprintf("must have a length greater than %s", fe.Param())
+	case "lt":
+		if isNumber {
+			return fmt.Sprintf("must be less than %s", fe.Param())
+		}
+		return fmt.Sprintf("must have a length less than %s", fe.Param())
+	default:
+		if fe.Param() != "" {
+			return fmt.Sprintf("failed the %q validation with parameter %q", fe.Tag(), fe.Param())
+		}
+		return fmt.Sprintf("failed the %q validation", fe.Tag())
+	}
+}
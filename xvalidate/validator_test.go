@@ -0,0 +1,174 @@
+package xvalidate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+	"github.com/tobbstr/xerror"
+	"google.golang.org/grpc/codes"
+)
+
+type emailAddress struct {
+	Email string   `json:"email" validate:"required,email"`
+	Type  []string `json:"type" validate:"dive,oneof=HOME WORK"`
+}
+
+type createContactRequest struct {
+	FullName       string         `json:"fullName" validate:"required"`
+	Age            int            `json:"age" validate:"gte=18,lte=65"`
+	UserID         string         `json:"userId" validate:"omitempty,uuid"`
+	EmailAddresses []emailAddress `json:"emailAddresses" validate:"dive"`
+}
+
+func TestFromValidator(t *testing.T) {
+	type given struct {
+		req       createContactRequest
+		opts      Options
+		jsonNames bool
+	}
+	type want struct {
+		code       codes.Code
+		violations []xerror.BadRequestViolation
+	}
+	validReq := func() createContactRequest {
+		return createContactRequest{
+			FullName: "John Doe",
+			Age:      30,
+			EmailAddresses: []emailAddress{
+				{Email: "john@example.com", Type: []string{"HOME"}},
+			},
+		}
+	}
+	tests := []struct {
+		name  string
+		given given
+		want  *want
+	}{
+		{
+			name:  "valid request",
+			given: given{req: validReq()},
+		},
+		{
+			name: "invalid arguments with json naming",
+			given: given{
+				req: func() createContactRequest {
+					req := validReq()
+					req.FullName = ""
+					req.UserID = "not-a-uuid"
+					req.EmailAddresses = append(req.EmailAddresses, emailAddress{Email: "john", Type: []string{"HOME", "OTHER"}})
+					return req
+				}(),
+			},
+			want: &want{
+				code: codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{
+					{Field: "fullName", Description: "must be set"},
+					{Field: "userId", Description: "must be a valid UUID"},
+					{Field: "emailAddresses[1].email", Description: "must be a valid email address"},
+					{Field: "emailAddresses[1].type[1]", Description: "must be one of [HOME WORK]"},
+				},
+			},
+		},
+		{
+			name: "invalid arguments with proto naming",
+			given: given{
+				req: func() createContactRequest {
+					req := validReq()
+					req.UserID = "not-a-uuid"
+					req.EmailAddresses[0].Type = []string{"HOME", "OTHER"}
+					return req
+				}(),
+				opts: Options{Naming: FieldNamingProto},
+			},
+			want: &want{
+				code: codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{
+					{Field: "user_id", Description: "must be a valid UUID"},
+					{Field: "email_addresses[0].type[1]", Description: "must be one of [HOME WORK]"},
+				},
+			},
+		},
+		{
+			name: "json tag names",
+			given: given{
+				req: func() createContactRequest {
+					req := validReq()
+					req.UserID = "not-a-uuid"
+					return req
+				}(),
+				jsonNames: true,
+			},
+			want: &want{
+				code:       codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{{Field: "userId", Description: "must be a valid UUID"}},
+			},
+		},
+		{
+			name: "out of range",
+			given: given{
+				req: func() createContactRequest {
+					req := validReq()
+					req.Age = 17
+					return req
+				}(),
+			},
+			want: &want{
+				code:       codes.OutOfRange,
+				violations: []xerror.BadRequestViolation{{Field: "age", Description: "must be greater than or equal to 18"}},
+			},
+		},
+		{
+			name: "out of range mixed with invalid argument",
+			given: given{
+				req: func() createContactRequest {
+					req := validReq()
+					req.FullName = ""
+					req.Age = 66
+					return req
+				}(),
+			},
+			want: &want{
+				code: codes.InvalidArgument,
+				violations: []xerror.BadRequestViolation{
+					{Field: "fullName", Description: "must be set"},
+					{Field: "age", Description: "must be less than or equal to 65"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			/* ---------------------------------- Given --------------------------------- */
+			validate := validator.New()
+			if tt.given.jsonNames {
+				validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+					name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+					return name
+				})
+			}
+
+			/* ---------------------------------- When ---------------------------------- */
+			got := FromValidator(validate.Struct(tt.given.req), tt.given.opts)
+
+			/* ---------------------------------- Then ---------------------------------- */
+			require := require.New(t)
+			if tt.want == nil {
+				require.NoError(got)
+				return
+			}
+			var xerr *xerror.Error
+			require.ErrorAs(got, &xerr)
+			require.Equal(tt.want.code, xerr.StatusCode())
+			require.Equal(tt.want.violations, xerr.BadRequestViolations())
+		})
+	}
+}
+
+func TestFromValidator_NonValidationError(t *testing.T) {
+	got := FromValidator(errors.New("validator: (nil *xvalidate.createContactRequest)"), Options{})
+	require.Equal(t, codes.Internal, xerror.From(got).StatusCode())
+}